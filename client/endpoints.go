@@ -0,0 +1,243 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Selection decides which node of endpoint pool serves next request
+type Selection string
+
+const (
+	//Failover sends every request to first healthy node, in the order endpoints are provided
+	Failover Selection = "failover"
+	//RoundRobin sends requests to healthy nodes in turn
+	RoundRobin Selection = "round-robin"
+)
+
+//unhealthyDuration is how long a node is skipped after connection to it fails
+const unhealthyDuration = 30 * time.Second
+
+type node struct {
+	endpoint       *url.URL
+	unhealthyUntil time.Time
+}
+
+//EndpointPool is http.RoundTripper which sends requests to nodes of cluster. Scheme and host of request are replaced
+//by the selected node, and path prefix of base endpoint, which request is built with, is replaced by path prefix of
+//the selected node, like for nodes behind reverse proxies. Nodes are marked unhealthy on connection errors, and if
+//node cannot be connected to, request is sent to next healthy node, since it didn't reach the cluster. Fallback
+//nodes are used only if none of the nodes can be connected to
+type EndpointPool struct {
+	next      http.RoundTripper
+	base      *url.URL
+	nodes     []*node
+	fallback  []*node
+	selection Selection
+	lock      sync.Mutex
+	cursor    int
+	now       func() time.Time
+}
+
+//NewEndpointPool returns pool which sends requests to endpoints using next round tripper. Requests are expected
+//to be built with first of endpoints
+func NewEndpointPool(next http.RoundTripper, endpoints []*url.URL, selection Selection) (*EndpointPool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("endpoint pool requires at least one endpoint")
+	}
+	if selection != Failover && selection != RoundRobin {
+		return nil, fmt.Errorf("invalid endpoint selection %s. Options are %s, %s", selection, Failover, RoundRobin)
+	}
+	pool := &EndpointPool{
+		next:      next,
+		base:      endpoints[0],
+		selection: selection,
+		now:       time.Now,
+	}
+	for _, endpoint := range endpoints {
+		pool.nodes = append(pool.nodes, &node{endpoint: endpoint})
+	}
+	return pool, nil
+}
+
+//UseEndpoints sends requests of client to nodes of endpoints, replacing endpoints used earlier. If there is only
+//one endpoint, requests are sent as they are
+func (c *Client) UseEndpoints(endpoints []*url.URL, selection Selection) error {
+	return c.UseEndpointsWithFallback(endpoints, nil, selection)
+}
+
+//UseEndpointsWithFallback is same as UseEndpoints, but requests are sent to fallback endpoints, in the order they
+//are provided, if none of endpoints can be connected to. Fallback endpoints which are also in endpoints are ignored.
+//Requests are expected to be built with first of fallback endpoints, if there are any
+func (c *Client) UseEndpointsWithFallback(endpoints []*url.URL, fallback []*url.URL, selection Selection) error {
+	next := c.HTTPClient.HTTPClient.Transport
+	if pool, ok := next.(*EndpointPool); ok {
+		next = pool.next
+	}
+	var base *url.URL
+	if len(fallback) > 0 {
+		base = fallback[0]
+	}
+	fallback = withoutEndpoints(fallback, endpoints)
+	if len(endpoints) < 2 && len(fallback) == 0 {
+		c.HTTPClient.HTTPClient.Transport = next
+		return nil
+	}
+	pool, err := NewEndpointPool(next, endpoints, selection)
+	if err != nil {
+		return err
+	}
+	if base != nil {
+		pool.base = base
+	}
+	for _, endpoint := range fallback {
+		pool.fallback = append(pool.fallback, &node{endpoint: endpoint})
+	}
+	c.HTTPClient.HTTPClient.Transport = pool
+	return nil
+}
+
+//withoutEndpoints returns endpoints whose scheme and host are not used by any of excluded
+func withoutEndpoints(endpoints []*url.URL, excluded []*url.URL) []*url.URL {
+	var result []*url.URL
+	for _, endpoint := range endpoints {
+		found := false
+		for _, other := range excluded {
+			if endpoint.Scheme == other.Scheme && endpoint.Host == other.Host {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
+
+//Unwrap returns round tripper used to send requests to nodes
+func (p *EndpointPool) Unwrap() http.RoundTripper {
+	return p.next
+}
+
+//candidates returns nodes in the order they should be tried, healthy nodes first, followed by healthy fallback
+//nodes, and then unhealthy nodes which will become healthy earliest, so that requests are sent even if every
+//node is unhealthy
+func (p *EndpointPool) candidates() []*node {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	start := 0
+	if p.selection == RoundRobin {
+		start = p.cursor
+		p.cursor = (p.cursor + 1) % len(p.nodes)
+	}
+	now := p.now()
+	var healthy, unhealthy []*node
+	for i := range p.nodes {
+		n := p.nodes[(start+i)%len(p.nodes)]
+		if now.Before(n.unhealthyUntil) {
+			unhealthy = append(unhealthy, n)
+			continue
+		}
+		healthy = append(healthy, n)
+	}
+	for _, n := range p.fallback {
+		if now.Before(n.unhealthyUntil) {
+			unhealthy = append(unhealthy, n)
+			continue
+		}
+		healthy = append(healthy, n)
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].unhealthyUntil.Before(unhealthy[j].unhealthyUntil)
+	})
+	return append(healthy, unhealthy...)
+}
+
+func (p *EndpointPool) setHealthy(n *node, healthy bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if healthy {
+		n.unhealthyUntil = time.Time{}
+		return
+	}
+	n.unhealthyUntil = p.now().Add(unhealthyDuration)
+}
+
+//RoundTrip sends request to selected node, and to next nodes if connection cannot be established
+func (p *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	var lastErr error
+	for _, n := range p.candidates() {
+		nodeReq := req.Clone(req.Context())
+		nodeReq.URL.Scheme = n.endpoint.Scheme
+		nodeReq.URL.Host = n.endpoint.Host
+		nodeReq.Host = ""
+		replacePathPrefix(nodeReq.URL, p.base, n.endpoint)
+		if body != nil {
+			nodeReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := p.next.RoundTrip(nodeReq)
+		if err == nil {
+			p.setHealthy(n, true)
+			//response refers to original request, so that hooks of client, like tracer, can match them
+			resp.Request = req
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			//request is canceled or timed out, which doesn't tell anything about node
+			break
+		}
+		p.setHealthy(n, false)
+		if !isDialError(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+//replacePathPrefix replaces path prefix of base endpoint in u by path prefix of endpoint. Path of u is not changed
+//if it doesn't start with path prefix of base endpoint
+func replacePathPrefix(u *url.URL, base *url.URL, endpoint *url.URL) {
+	from, to := strings.TrimSuffix(base.Path, "/"), strings.TrimSuffix(endpoint.Path, "/")
+	rest := strings.TrimPrefix(u.Path, from)
+	if from == to || !strings.HasPrefix(u.Path, from) || (len(rest) > 0 && !strings.HasPrefix(rest, "/")) {
+		return
+	}
+	u.Path = to + rest
+	if len(u.RawPath) > 0 {
+		rawFrom, rawTo := strings.TrimSuffix(base.EscapedPath(), "/"), strings.TrimSuffix(endpoint.EscapedPath(), "/")
+		u.RawPath = rawTo + strings.TrimPrefix(u.RawPath, rawFrom)
+	}
+}
+
+//isDialError checks whether connection couldn't be established, hence, request was not sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
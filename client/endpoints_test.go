@@ -0,0 +1,191 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//newNode starts server which responds with its name and body of request
+func newNode(t *testing.T, name string) *url.URL {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(name + string(body)))
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	return u
+}
+
+//newDeadNode returns endpoint which refuses connections
+func newDeadNode(t *testing.T) *url.URL {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	u, err := url.Parse("http://" + listener.Addr().String())
+	assert.NoError(t, err)
+	assert.NoError(t, listener.Close())
+	return u
+}
+
+func newPoolClient(t *testing.T, selection Selection, endpoints ...*url.URL) *Client {
+	c, err := New(nil)
+	assert.NoError(t, err)
+	c.HTTPClient.RetryMax = 0
+	assert.NoError(t, c.UseEndpoints(endpoints, selection))
+	return c
+}
+
+func TestEndpointPool(t *testing.T) {
+	t.Run("failover to next node", func(t *testing.T) {
+		dead, alive := newDeadNode(t), newNode(t, "node-2")
+		c := newPoolClient(t, Failover, dead, alive)
+		for i := 0; i < 2; i++ {
+			status, body, err := doRequest(t, c, http.MethodPost, dead.String()+"/movies/_doc", []byte(":movie"))
+			assert.NoError(t, err)
+			assert.EqualValues(t, http.StatusOK, status)
+			assert.EqualValues(t, "node-2:movie", body)
+		}
+		pool := c.HTTPClient.HTTPClient.Transport.(*EndpointPool)
+		assert.True(t, pool.nodes[0].unhealthyUntil.After(time.Now()))
+	})
+	t.Run("round robin", func(t *testing.T) {
+		first, second := newNode(t, "node-1"), newNode(t, "node-2")
+		c := newPoolClient(t, RoundRobin, first, second)
+		var bodies []string
+		for i := 0; i < 3; i++ {
+			_, body, err := doRequest(t, c, http.MethodGet, first.String()+"/_cluster/health", nil)
+			assert.NoError(t, err)
+			bodies = append(bodies, body)
+		}
+		assert.EqualValues(t, []string{"node-1", "node-2", "node-1"}, bodies)
+	})
+	t.Run("unhealthy node is used once it recovers", func(t *testing.T) {
+		first, second := newNode(t, "node-1"), newNode(t, "node-2")
+		c := newPoolClient(t, Failover, first, second)
+		pool := c.HTTPClient.HTTPClient.Transport.(*EndpointPool)
+		now := time.Now()
+		pool.now = func() time.Time { return now }
+		pool.setHealthy(pool.nodes[0], false)
+		_, body, err := doRequest(t, c, http.MethodGet, first.String(), nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "node-2", body)
+		now = now.Add(unhealthyDuration)
+		_, body, err = doRequest(t, c, http.MethodGet, first.String(), nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "node-1", body)
+	})
+	t.Run("every node is down", func(t *testing.T) {
+		c := newPoolClient(t, Failover, newDeadNode(t), newDeadNode(t))
+		_, _, err := doRequest(t, c, http.MethodGet, "http://localhost:9200", nil)
+		assert.Error(t, err)
+	})
+	t.Run("request which reached node is not sent again", func(t *testing.T) {
+		attempts := 0
+		pool, err := NewEndpointPool(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset by peer")
+		}), []*url.URL{newNode(t, "node-1"), newNode(t, "node-2")}, Failover)
+		assert.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:9200/movies/_doc", nil)
+		_, err = pool.RoundTrip(req)
+		assert.EqualError(t, err, "connection reset by peer")
+		assert.EqualValues(t, 1, attempts)
+	})
+	t.Run("path prefix of node replaces path prefix of base endpoint", func(t *testing.T) {
+		var urls []string
+		next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			if req.URL.Host != "node-3" {
+				return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		})
+		endpoints := []*url.URL{
+			{Scheme: "https", Host: "node-1", Path: "/opensearch/"},
+			{Scheme: "https", Host: "node-2"},
+			{Scheme: "http", Host: "node-3", Path: "/search"},
+		}
+		pool, err := NewEndpointPool(next, endpoints, Failover)
+		assert.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodGet, "https://node-1/opensearch/movies/_doc/a%2Fb", nil)
+		_, err = pool.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{
+			"https://node-1/opensearch/movies/_doc/a%2Fb",
+			"https://node-2/movies/_doc/a%2Fb",
+			"http://node-3/search/movies/_doc/a%2Fb",
+		}, urls)
+	})
+	t.Run("path prefix of fallback is base of discovered nodes", func(t *testing.T) {
+		var paths []string
+		c, err := New(nil)
+		assert.NoError(t, err)
+		c.HTTPClient.RetryMax = 0
+		c.HTTPClient.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		})
+		fallback := &url.URL{Scheme: "https", Host: "proxy", Path: "/opensearch"}
+		assert.NoError(t, c.UseEndpointsWithFallback([]*url.URL{{Scheme: "http", Host: "node-1"}}, []*url.URL{fallback}, RoundRobin))
+		_, _, err = doRequest(t, c, http.MethodGet, "https://proxy/opensearch/_cat/indices", nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"/_cat/indices"}, paths)
+	})
+	t.Run("fallback is used only if no node can be connected to", func(t *testing.T) {
+		first, second, fallback := newNode(t, "node-1"), newDeadNode(t), newNode(t, "fallback")
+		c, err := New(nil)
+		assert.NoError(t, err)
+		c.HTTPClient.RetryMax = 0
+		assert.NoError(t, c.UseEndpointsWithFallback([]*url.URL{first, second}, []*url.URL{fallback, first}, RoundRobin))
+		pool := c.HTTPClient.HTTPClient.Transport.(*EndpointPool)
+		assert.Len(t, pool.fallback, 1, "fallback which is also a node is ignored")
+		var bodies []string
+		for i := 0; i < 2; i++ {
+			_, body, err := doRequest(t, c, http.MethodGet, first.String(), nil)
+			assert.NoError(t, err)
+			bodies = append(bodies, body)
+		}
+		assert.EqualValues(t, []string{"node-1", "node-1"}, bodies)
+		pool.setHealthy(pool.nodes[0], false)
+		_, body, err := doRequest(t, c, http.MethodGet, first.String(), nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "fallback", body)
+	})
+	t.Run("single endpoint with fallback", func(t *testing.T) {
+		c, err := New(nil)
+		assert.NoError(t, err)
+		c.HTTPClient.RetryMax = 0
+		assert.NoError(t, c.UseEndpointsWithFallback([]*url.URL{newDeadNode(t)}, []*url.URL{newNode(t, "fallback")}, RoundRobin))
+		_, body, err := doRequest(t, c, http.MethodGet, "http://localhost:9200", nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "fallback", body)
+	})
+	t.Run("invalid selection", func(t *testing.T) {
+		c, err := New(nil)
+		assert.NoError(t, err)
+		err = c.UseEndpoints([]*url.URL{newNode(t, "node-1"), newNode(t, "node-2")}, "random")
+		assert.EqualError(t, err, "invalid endpoint selection random. Options are failover, round-robin")
+	})
+	t.Run("single endpoint removes pool", func(t *testing.T) {
+		c := newPoolClient(t, Failover, newNode(t, "node-1"), newNode(t, "node-2"))
+		assert.NoError(t, c.UseEndpoints(nil, Failover))
+		_, ok := c.HTTPClient.HTTPClient.Transport.(*http.Transport)
+		assert.True(t, ok)
+	})
+}
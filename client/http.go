@@ -26,7 +26,6 @@
 package client
 
 import (
-	"crypto/tls"
 	"net/http"
 	"time"
 
@@ -47,17 +46,21 @@ func NewDefaultClient(tripper http.RoundTripper) (*Client, error) {
 	client.HTTPClient.Transport = tripper
 	client.HTTPClient.Timeout = defaultTimeout * time.Second
 	client.Logger = nil
-	return &Client{
+	// return last response instead of generic error once retries are exhausted,
+	// so that status code and error response from cluster are available to caller
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	c := &Client{
 		HTTPClient: client,
-	}, nil
+	}
+	c.SetRetryPolicy(DefaultRetryPolicy())
+	return c, nil
 }
 
 //New takes transport and uses accordingly
 func New(tripper http.RoundTripper) (*Client, error) {
 	if tripper == nil {
-		tripper = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
+		// server's certificate is verified using system's certificate pool unless profile configures otherwise
+		tripper = http.DefaultTransport.(*http.Transport).Clone()
 	}
 	return NewDefaultClient(tripper)
 }
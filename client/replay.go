@@ -0,0 +1,249 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	fixtureFolderPermission = 0700
+	fixtureFilePermission   = 0600
+	fixtureFilePattern      = "*.json"
+)
+
+//Interaction is a request and its response saved as fixture file by Recorder
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+//RecordedRequest holds request sent to cluster, secrets in headers, url and json body are scrubbed
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+//RecordedResponse holds response received from cluster, secrets in headers and json body are scrubbed
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+//Recorder is a http.RoundTripper which saves every request and its response as fixture file in a folder,
+//so that they can be served by Replayer later
+type Recorder struct {
+	dir   string
+	next  http.RoundTripper
+	lock  sync.Mutex
+	count int
+}
+
+//NewRecorder returns recorder which sends requests using next and saves them in dir, dir is created if it doesn't exist.
+//Fixtures are added to fixtures which already exist in dir. If next is nil, http.DefaultTransport is used
+func NewRecorder(dir string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if err := os.MkdirAll(dir, fixtureFolderPermission); err != nil {
+		return nil, fmt.Errorf("failed to create folder to record requests due to: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, fixtureFilePattern))
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		dir:   dir,
+		next:  next,
+		count: len(files),
+	}, nil
+}
+
+//Unwrap returns underlying round tripper, so that transport settings of profile can be applied to it
+func (r *Recorder) Unwrap() http.RoundTripper {
+	return r.next
+}
+
+//RoundTrip sends request using underlying round tripper and saves request along with its response. Values of
+//secret fields of json bodies, like password or token, are redacted, other data in bodies is saved as it is
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: scrubHeader(req.Header),
+			Body:   redactBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+			Body:       redactBody(respBody),
+		},
+	}
+	if err = r.save(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+//save writes interaction to next fixture file, files are numbered in the order requests are made
+func (r *Recorder) save(interaction Interaction) error {
+	contents, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.count++
+	name := fmt.Sprintf("%04d-%s.json", r.count, strings.ToLower(interaction.Request.Method))
+	if err = ioutil.WriteFile(filepath.Join(r.dir, name), contents, fixtureFilePermission); err != nil {
+		return fmt.Errorf("failed to record request due to: %w", err)
+	}
+	return nil
+}
+
+//NotRecordedError represents request which doesn't match any recorded request
+type NotRecordedError struct {
+	Dir    string
+	Method string
+	URI    string
+}
+
+//Error inherits error interface to pass as error
+func (e *NotRecordedError) Error() string {
+	return fmt.Sprintf("no recorded response found in %s for %s %s", e.Dir, e.Method, e.URI)
+}
+
+//Replayer is a http.RoundTripper which serves responses from fixture files saved by Recorder, without
+//connecting to cluster
+type Replayer struct {
+	dir          string
+	lock         sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+//NewReplayer loads fixture files from dir, fixtures are served in the order they were recorded
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, fixtureFilePattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded requests found in %s", dir)
+	}
+	sort.Strings(files)
+	r := &Replayer{dir: dir}
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recorded request due to: %w", err)
+		}
+		var interaction Interaction
+		if err = json.Unmarshal(contents, &interaction); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded request %s due to: %w", file, err)
+		}
+		r.interactions = append(r.interactions, interaction)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+//RoundTrip returns response of first recorded request which is not served yet and matches method, path,
+//query and body of request. Host is not matched, so that fixtures can be replayed against any endpoint
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, &NotRecordedError{Dir: r.dir, Method: req.Method, URI: req.URL.RequestURI()}
+}
+
+//matches checks whether recorded request has same method, path, query and body as request, secrets of body are
+//redacted before it is compared, since they are redacted in recorded request
+func matches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method || recorded.Body != redactBody(body) {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return recordedURL.Path == req.URL.Path && recordedURL.Query().Encode() == req.URL.Query().Encode()
+}
+
+//readBody reads body and replaces it with a reader of same contents, so that it can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	contents, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(contents))
+	return contents, err
+}
+
+//scrubHeader returns copy of header where secrets are redacted
+func scrubHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	result := make(http.Header, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			result.Add(name, redactHeader(name, value))
+		}
+	}
+	return result
+}
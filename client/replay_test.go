@@ -0,0 +1,147 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func doRequest(t *testing.T, c *Client, method string, url string, body []byte) (int, string, error) {
+	req, err := retryablehttp.NewRequest(method, url, body)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(contents), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, `{"call":%d,"request":%q}`, calls, string(body))
+	}))
+	defer server.Close()
+
+	recorder, err := NewRecorder(dir, nil)
+	assert.NoError(t, err)
+	c, err := New(recorder)
+	assert.NoError(t, err)
+	status, body, err := doRequest(t, c, http.MethodPost, server.URL+"/movies/_search?size=1", []byte(`{"query":{}}`))
+	assert.NoError(t, err)
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, `{"call":1,"request":"{\"query\":{}}"}`, body, "response must be available after it is recorded")
+	_, _, err = doRequest(t, c, http.MethodGet, server.URL+"/missing", nil)
+	assert.NoError(t, err)
+	_, _, err = doRequest(t, c, http.MethodPost, server.URL+"/movies/_search?size=1", []byte(`{"query":{}}`))
+	assert.NoError(t, err)
+
+	t.Run("secrets are scrubbed", func(t *testing.T) {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		assert.NoError(t, err)
+		assert.Len(t, files, 3)
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "0001-post.json"))
+		assert.NoError(t, err)
+		var interaction Interaction
+		assert.NoError(t, json.Unmarshal(contents, &interaction))
+		assert.EqualValues(t, "Basic [REDACTED]", interaction.Request.Header.Get("Authorization"))
+		assert.EqualValues(t, "[REDACTED]", interaction.Response.Header.Get("Set-Cookie"))
+		assert.EqualValues(t, `{"query":{}}`, interaction.Request.Body)
+		assert.EqualValues(t, http.StatusOK, interaction.Response.StatusCode)
+	})
+	t.Run("recording continues after existing fixtures", func(t *testing.T) {
+		other, err := NewRecorder(dir, nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, other.count)
+	})
+	t.Run("responses are replayed in order", func(t *testing.T) {
+		replayer, err := NewReplayer(dir)
+		assert.NoError(t, err)
+		c, err := New(replayer)
+		assert.NoError(t, err)
+		status, body, err := doRequest(t, c, http.MethodGet, "https://other-host:9200/missing", nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, http.StatusNotFound, status)
+		assert.EqualValues(t, `{"call":2,"request":""}`, body)
+		_, body, err = doRequest(t, c, http.MethodPost, "https://other-host:9200/movies/_search?size=1", []byte(`{"query":{}}`))
+		assert.NoError(t, err)
+		assert.EqualValues(t, `{"call":1,"request":"{\"query\":{}}"}`, body)
+		_, body, err = doRequest(t, c, http.MethodPost, "https://other-host:9200/movies/_search?size=1", []byte(`{"query":{}}`))
+		assert.NoError(t, err)
+		assert.EqualValues(t, `{"call":3,"request":"{\"query\":{}}"}`, body)
+		assert.EqualValues(t, 3, calls, "cluster must not be called while replaying")
+	})
+	t.Run("request is not recorded", func(t *testing.T) {
+		replayer, err := NewReplayer(dir)
+		assert.NoError(t, err)
+		c, err := New(replayer)
+		assert.NoError(t, err)
+		_, _, err = doRequest(t, c, http.MethodPost, "https://localhost:9200/movies/_search?size=2", []byte(`{"query":{}}`))
+		var notRecorded *NotRecordedError
+		assert.True(t, errors.As(err, &notRecorded))
+		assert.EqualValues(t, "/movies/_search?size=2", notRecorded.URI)
+	})
+	t.Run("secrets of json body are scrubbed", func(t *testing.T) {
+		secrets := t.TempDir()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"access_token":"abc","expires_in":60}`)
+		}))
+		defer server.Close()
+		recorder, err := NewRecorder(secrets, nil)
+		assert.NoError(t, err)
+		c, err := New(recorder)
+		assert.NoError(t, err)
+		user := []byte(`{"password":"admin","backend_roles":["admin"]}`)
+		_, body, err := doRequest(t, c, http.MethodPut, server.URL+"/_plugins/_security/api/internalusers/admin", user)
+		assert.NoError(t, err)
+		assert.EqualValues(t, `{"access_token":"abc","expires_in":60}`, body, "response is not redacted for command")
+
+		contents, err := ioutil.ReadFile(filepath.Join(secrets, "0001-put.json"))
+		assert.NoError(t, err)
+		var interaction Interaction
+		assert.NoError(t, json.Unmarshal(contents, &interaction))
+		assert.EqualValues(t, `{"backend_roles":["admin"],"password":"[REDACTED]"}`, interaction.Request.Body)
+		assert.EqualValues(t, `{"access_token":"[REDACTED]","expires_in":60}`, interaction.Response.Body)
+
+		replayer, err := NewReplayer(secrets)
+		assert.NoError(t, err)
+		c, err = New(replayer)
+		assert.NoError(t, err)
+		_, body, err = doRequest(t, c, http.MethodPut, "https://other-host:9200/_plugins/_security/api/internalusers/admin", user)
+		assert.NoError(t, err, "request with secrets matches redacted recorded request")
+		assert.EqualValues(t, `{"access_token":"[REDACTED]","expires_in":60}`, body)
+	})
+	t.Run("no fixtures", func(t *testing.T) {
+		empty := t.TempDir()
+		_, err := NewReplayer(empty)
+		assert.EqualError(t, err, fmt.Sprintf("no recorded requests found in %s", empty))
+	})
+}
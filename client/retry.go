@@ -0,0 +1,173 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+//DefaultRetryStatusCodes are status codes returned by clusters under load or while nodes are restarting
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+//idempotentPostAPIs are APIs which only read data, even though they are called using POST
+var idempotentPostAPIs = map[string]bool{
+	"_search":  true,
+	"_msearch": true,
+	"_count":   true,
+	"_mget":    true,
+}
+
+type requestKey struct{}
+
+//requestInfo is method and path of request, carried by context of request
+type requestInfo struct {
+	method string
+	path   string
+}
+
+//WithRequest returns context which carries method and path of request, so that retry policy can decide whether
+//request is idempotent even if request failed without response
+func WithRequest(ctx context.Context, method string, path string) context.Context {
+	return context.WithValue(ctx, requestKey{}, requestInfo{method: method, path: path})
+}
+
+//RetryPolicy decides which failed requests are retried and how long to wait before retrying them
+type RetryPolicy struct {
+	//MinBackoff is wait before first retry, wait is doubled for every retry until it reaches MaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	//Jitter waits for random duration between half and full backoff, so that clients don't retry together
+	Jitter bool
+	//StatusCodes are retried, requests failed due to connection errors are always retried
+	StatusCodes []int
+	//HonorRetryAfter waits as long as Retry-After header asks, up to MaxBackoff
+	HonorRetryAfter bool
+	//RetryNonIdempotent retries POST and PATCH requests which may have modified data before failing, requests
+	//which failed to connect are always retried, since they were never sent
+	RetryNonIdempotent bool
+}
+
+//DefaultRetryPolicy returns policy used unless profile configures retry
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MinBackoff:      defaultMinBackoff,
+		MaxBackoff:      defaultMaxBackoff,
+		StatusCodes:     DefaultRetryStatusCodes,
+		HonorRetryAfter: true,
+	}
+}
+
+//SetRetryPolicy replaces retry policy and backoff of client
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.HTTPClient.RetryWaitMin = policy.MinBackoff
+	c.HTTPClient.RetryWaitMax = policy.MaxBackoff
+	c.HTTPClient.CheckRetry = policy.CheckRetry
+	c.HTTPClient.Backoff = policy.Backoff
+}
+
+//CheckRetry implements retryablehttp.CheckRetry. Requests which are not recorded are never retried, since replaying
+//them again gives same result
+func (p RetryPolicy) CheckRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	var notRecorded *NotRecordedError
+	if errors.As(err, &notRecorded) {
+		return false, err
+	}
+	//request which couldn't connect was never sent, hence, it is retried even if it is not idempotent
+	if !p.RetryNonIdempotent && !isIdempotent(ctx, resp) && !isDialError(err) {
+		return false, nil
+	}
+	if err != nil {
+		//connection errors are retried unless they are permanent, like invalid certificate
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	for _, code := range p.StatusCodes {
+		if resp.StatusCode == code {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+//isIdempotent checks method and path of request from context, or from response if context doesn't have them
+func isIdempotent(ctx context.Context, resp *http.Response) bool {
+	info, ok := ctx.Value(requestKey{}).(requestInfo)
+	if !ok && resp != nil && resp.Request != nil {
+		info = requestInfo{method: resp.Request.Method, path: resp.Request.URL.Path}
+	}
+	switch info.method {
+	case http.MethodPatch:
+		return false
+	case http.MethodPost:
+		return idempotentPostAPIs[path.Base(info.path)]
+	}
+	return true
+}
+
+//Backoff implements retryablehttp.Backoff, min and max are RetryWaitMin and RetryWaitMax of client
+func (p RetryPolicy) Backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if p.HonorRetryAfter {
+		if wait, ok := retryAfter(resp); ok {
+			if wait > max {
+				return max
+			}
+			return wait
+		}
+	}
+	wait := max
+	if backoff := math.Pow(2, float64(attemptNum)) * float64(min); backoff < float64(max) {
+		wait = time.Duration(backoff)
+	}
+	if p.Jitter && wait > 1 {
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+	return wait
+}
+
+//retryAfter parses Retry-After header of response, which is either seconds or HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
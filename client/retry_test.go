@@ -0,0 +1,139 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetriedClient(t *testing.T, policy RetryPolicy, failure error, responses ...int) (*Client, *int) {
+	attempts := 0
+	c, err := New(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if failure != nil {
+			return nil, failure
+		}
+		status := responses[attempts-1]
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Body:       ioutil.NopCloser(strings.NewReader("{}")),
+			Request:    req,
+		}, nil
+	}))
+	assert.NoError(t, err)
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+	c.SetRetryPolicy(policy)
+	c.HTTPClient.RetryMax = 2
+	return c, &attempts
+}
+
+func doRetriedRequest(t *testing.T, c *Client, method string, path string) (*http.Response, error) {
+	req, err := retryablehttp.NewRequest(method, "https://localhost:9200"+path, nil)
+	assert.NoError(t, err)
+	req = req.WithContext(WithRequest(context.Background(), method, path))
+	return c.HTTPClient.Do(req)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retry status codes", func(t *testing.T) {
+		c, attempts := newRetriedClient(t, DefaultRetryPolicy(), nil, http.StatusTooManyRequests, http.StatusGatewayTimeout, http.StatusOK)
+		resp, err := doRetriedRequest(t, c, http.MethodGet, "/_cluster/health")
+		assert.NoError(t, err)
+		assert.EqualValues(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 3, *attempts)
+	})
+	t.Run("status code is not retried", func(t *testing.T) {
+		c, attempts := newRetriedClient(t, DefaultRetryPolicy(), nil, http.StatusInternalServerError)
+		resp, err := doRetriedRequest(t, c, http.MethodGet, "/_cluster/health")
+		assert.NoError(t, err)
+		assert.EqualValues(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.EqualValues(t, 1, *attempts)
+	})
+	t.Run("custom status codes", func(t *testing.T) {
+		policy := DefaultRetryPolicy()
+		policy.StatusCodes = []int{http.StatusInternalServerError}
+		c, attempts := newRetriedClient(t, policy, nil, http.StatusInternalServerError, http.StatusOK)
+		resp, err := doRetriedRequest(t, c, http.MethodGet, "/_cluster/health")
+		assert.NoError(t, err)
+		assert.EqualValues(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 2, *attempts)
+	})
+	t.Run("non idempotent request is not retried", func(t *testing.T) {
+		c, attempts := newRetriedClient(t, DefaultRetryPolicy(), errors.New("connection reset"))
+		_, err := doRetriedRequest(t, c, http.MethodPost, "/_plugins/_anomaly_detection/detectors")
+		assert.Error(t, err)
+		assert.EqualValues(t, 1, *attempts)
+	})
+	t.Run("non idempotent request which couldn't connect is retried", func(t *testing.T) {
+		refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		c, attempts := newRetriedClient(t, DefaultRetryPolicy(), refused)
+		_, err := doRetriedRequest(t, c, http.MethodPatch, "/_plugins/_security/api/internalusers/admin")
+		assert.Error(t, err)
+		assert.EqualValues(t, 3, *attempts)
+	})
+	t.Run("search using post is retried", func(t *testing.T) {
+		c, attempts := newRetriedClient(t, DefaultRetryPolicy(), errors.New("connection reset"))
+		_, err := doRetriedRequest(t, c, http.MethodPost, "/movies/_search")
+		assert.Error(t, err)
+		assert.EqualValues(t, 3, *attempts)
+	})
+	t.Run("non idempotent request is retried if allowed", func(t *testing.T) {
+		policy := DefaultRetryPolicy()
+		policy.RetryNonIdempotent = true
+		c, attempts := newRetriedClient(t, policy, nil, http.StatusServiceUnavailable, http.StatusCreated)
+		resp, err := doRetriedRequest(t, c, http.MethodPost, "/movies/_doc")
+		assert.NoError(t, err)
+		assert.EqualValues(t, http.StatusCreated, resp.StatusCode)
+		assert.EqualValues(t, 2, *attempts)
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	response := func(retryAfter string) *http.Response {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{retryAfter}}}
+	}
+	policy := DefaultRetryPolicy()
+	t.Run("exponential", func(t *testing.T) {
+		assert.EqualValues(t, time.Second, policy.Backoff(time.Second, 5*time.Second, 0, nil))
+		assert.EqualValues(t, 4*time.Second, policy.Backoff(time.Second, 5*time.Second, 2, nil))
+		assert.EqualValues(t, 5*time.Second, policy.Backoff(time.Second, 5*time.Second, 3, nil))
+	})
+	t.Run("retry after", func(t *testing.T) {
+		assert.EqualValues(t, 3*time.Second, policy.Backoff(time.Second, 5*time.Second, 0, response("3")))
+		assert.EqualValues(t, 5*time.Second, policy.Backoff(time.Second, 5*time.Second, 0, response("60")))
+		date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+		assert.EqualValues(t, 5*time.Second, policy.Backoff(time.Second, 5*time.Second, 0, response(date)))
+		assert.EqualValues(t, time.Second, policy.Backoff(time.Second, 5*time.Second, 0, response("invalid")))
+	})
+	t.Run("retry after is ignored", func(t *testing.T) {
+		ignored := policy
+		ignored.HonorRetryAfter = false
+		assert.EqualValues(t, time.Second, ignored.Backoff(time.Second, 5*time.Second, 0, response("3")))
+	})
+	t.Run("jitter", func(t *testing.T) {
+		jitter := policy
+		jitter.Jitter = true
+		for i := 0; i < 10; i++ {
+			wait := jitter.Backoff(time.Second, 5*time.Second, 2, nil)
+			assert.True(t, wait >= 2*time.Second && wait <= 4*time.Second, wait)
+		}
+	})
+}
@@ -0,0 +1,342 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+//TraceLevel decides how much of every request and response is logged
+type TraceLevel int
+
+const (
+	//TraceOff doesn't log anything
+	TraceOff TraceLevel = iota
+	//TraceHeaders logs method, url, headers, body sizes, status, retries and timings
+	TraceHeaders
+	//TraceBodies logs bodies of request and response too
+	TraceBodies
+)
+
+const (
+	redacted = "[REDACTED]"
+	//maxTraceBodySize limits body written by tracer, so that large responses don't flood the console
+	maxTraceBodySize = 64 * 1024
+)
+
+//secretHeaders are never logged, authorization headers keep only their scheme
+var secretHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+}
+
+//secretFields are keys of json bodies whose values are redacted, along with keys which have one of secretFieldSuffixes
+var secretFields = map[string]bool{
+	"password":    true,
+	"passwd":      true,
+	"hash":        true,
+	"secret":      true,
+	"token":       true,
+	"api_key":     true,
+	"apikey":      true,
+	"credential":  true,
+	"credentials": true,
+	"access_key":  true,
+	"secret_key":  true,
+	"private_key": true,
+}
+
+//secretFieldSuffixes match keys like bind_password, client_secret or session_token
+var secretFieldSuffixes = []string{"_password", "_secret", "_token"}
+
+//secretQueryParams are used by presigned SigV4 urls
+var secretQueryParams = []string{"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token"}
+
+//Tracer logs requests, responses and retries made by client, secrets are redacted
+type Tracer struct {
+	writer io.Writer
+	level  TraceLevel
+	lock   sync.Mutex
+	//started holds start time of attempts by url, since http.Client may pass a copy of request to transport,
+	//which shares url with original request
+	started map[*url.URL]time.Time
+}
+
+//EnableTrace logs every request made by client on writer, nothing is logged if level is TraceOff
+func (c *Client) EnableTrace(writer io.Writer, level TraceLevel) {
+	if level == TraceOff {
+		return
+	}
+	t := &Tracer{
+		writer:  writer,
+		level:   level,
+		started: map[*url.URL]time.Time{},
+	}
+	c.HTTPClient.Logger = t
+	c.HTTPClient.RequestLogHook = t.logRequest
+	c.HTTPClient.ResponseLogHook = t.logResponse
+}
+
+//logRequest logs request before every attempt
+func (t *Tracer) logRequest(_ retryablehttp.Logger, req *http.Request, retry int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.started[req.URL] = time.Now()
+	line := fmt.Sprintf("> %s %s", req.Method, redactURL(req.URL))
+	if retry > 0 {
+		line = fmt.Sprintf("%s (retry %d)", line, retry)
+	}
+	t.println(line)
+	t.printHeaders(">", req.Header)
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	if t.level < TraceBodies {
+		t.println(fmt.Sprintf("> body: %s", formatSize(req.ContentLength)))
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		t.println(fmt.Sprintf("* failed to read request body: %s", err))
+		return
+	}
+	t.printBody(">", body)
+}
+
+//logResponse logs response of every attempt
+func (t *Tracer) logResponse(_ retryablehttp.Logger, resp *http.Response) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	line := fmt.Sprintf("< %s %s", resp.Proto, resp.Status)
+	if resp.Request != nil {
+		if started, ok := t.started[resp.Request.URL]; ok {
+			line = fmt.Sprintf("%s (%s)", line, time.Since(started).Round(time.Millisecond))
+			delete(t.started, resp.Request.URL)
+		}
+	}
+	t.println(line)
+	t.printHeaders("<", resp.Header)
+	if t.level < TraceBodies {
+		t.println(fmt.Sprintf("< body: %s", formatSize(resp.ContentLength)))
+		return
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		t.println(fmt.Sprintf("* failed to read response body: %s", err))
+		return
+	}
+	t.printBody("<", body)
+}
+
+func (t *Tracer) printHeaders(prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			t.println(fmt.Sprintf("%s %s: %s", prefix, name, redactHeader(name, value)))
+		}
+	}
+}
+
+func (t *Tracer) printBody(prefix string, body []byte) {
+	t.println(fmt.Sprintf("%s body: %s", prefix, formatSize(int64(len(body)))))
+	if len(body) == 0 {
+		return
+	}
+	text := redactBody(body)
+	if len(text) > maxTraceBodySize {
+		t.println(text[:maxTraceBodySize])
+		t.println(fmt.Sprintf("* body is truncated to %s", formatSize(maxTraceBodySize)))
+		return
+	}
+	t.println(text)
+}
+
+func (t *Tracer) println(line string) {
+	fmt.Fprintln(t.writer, line)
+}
+
+//Error logs failed attempts, implements retryablehttp.LeveledLogger
+func (t *Tracer) Error(msg string, keysAndValues ...interface{}) {
+	t.forgetAttempt(keysAndValues)
+	t.log(msg, keysAndValues)
+}
+
+//forgetAttempt removes start time of attempt which failed without response, since it is removed only by response hook
+func (t *Tracer) forgetAttempt(keysAndValues []interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if u, ok := keysAndValues[i+1].(*url.URL); ok && keysAndValues[i] == "url" {
+			delete(t.started, u)
+		}
+	}
+}
+
+//Info implements retryablehttp.LeveledLogger
+func (t *Tracer) Info(msg string, keysAndValues ...interface{}) {
+	t.log(msg, keysAndValues)
+}
+
+//Debug logs retries, implements retryablehttp.LeveledLogger
+func (t *Tracer) Debug(msg string, keysAndValues ...interface{}) {
+	//requests are already logged by request hook
+	if msg == "performing request" {
+		return
+	}
+	t.log(msg, keysAndValues)
+}
+
+//Warn implements retryablehttp.LeveledLogger
+func (t *Tracer) Warn(msg string, keysAndValues ...interface{}) {
+	t.log(msg, keysAndValues)
+}
+
+//log writes message along with key value pairs, except url and request which are logged by request hook
+func (t *Tracer) log(msg string, keysAndValues []interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	line := "* " + msg
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if key == "url" || key == "request" || key == "method" {
+			continue
+		}
+		line = fmt.Sprintf("%s, %s: %v", line, key, keysAndValues[i+1])
+	}
+	t.println(line)
+}
+
+//redactHeader hides value of secret headers, scheme of authorization headers is kept, like Basic or AWS4-HMAC-SHA256
+func redactHeader(name string, value string) string {
+	name = http.CanonicalHeaderKey(name)
+	if !secretHeaders[name] {
+		return value
+	}
+	if name == "Authorization" || name == "Proxy-Authorization" {
+		if scheme := strings.Fields(value); len(scheme) > 1 {
+			return scheme[0] + " " + redacted
+		}
+	}
+	return redacted
+}
+
+//redactURL hides password of user info and signature of presigned urls
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	result := *u
+	query := result.Query()
+	modified := false
+	for _, param := range secretQueryParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, redacted)
+			modified = true
+		}
+	}
+	if modified {
+		result.RawQuery = query.Encode()
+	}
+	return result.Redacted()
+}
+
+//redactBody hides values of secret fields in json or newline delimited json body. Body which doesn't have any secret
+//field, or is not json, is returned as it is, so that it is not reformatted
+func redactBody(body []byte) string {
+	if json.Valid(body) {
+		return redactJSON(body)
+	}
+	lines := strings.Split(string(body), "\n")
+	for i, line := range lines {
+		if json.Valid([]byte(line)) {
+			lines[i] = redactJSON([]byte(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+//redactJSON hides values of secret fields in json document
+func redactJSON(document []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || !redactFields(value) {
+		return string(document)
+	}
+	var result bytes.Buffer
+	encoder := json.NewEncoder(&result)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return string(document)
+	}
+	return strings.TrimSuffix(result.String(), "\n")
+}
+
+//redactFields replaces values of secret fields in decoded json value, returns true if any field is redacted
+func redactFields(value interface{}) bool {
+	modified := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSecretField(key) {
+				v[key] = redacted
+				modified = true
+				continue
+			}
+			modified = redactFields(field) || modified
+		}
+	case []interface{}:
+		for _, item := range v {
+			modified = redactFields(item) || modified
+		}
+	}
+	return modified
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	if secretFields[key] {
+		return true
+	}
+	for _, suffix := range secretFieldSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSize(size int64) string {
+	if size < 0 {
+		return "unknown size"
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -26,13 +26,11 @@
 package commands
 
 import (
-	"opensearch-cli/client"
 	adctrl "opensearch-cli/controller/ad"
 	ctrl "opensearch-cli/controller/platform"
 	adgateway "opensearch-cli/gateway/ad"
 	gateway "opensearch-cli/gateway/platform"
 	handler "opensearch-cli/handler/ad"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -61,7 +59,7 @@ func GetADCommand() *cobra.Command {
 
 //GetADHandler returns handler by wiring the dependency manually
 func GetADHandler() (*handler.Handler, error) {
-	c, err := client.New(nil)
+	c, err := newClient()
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +76,6 @@ func GetADHandler() (*handler.Handler, error) {
 		return nil, err
 	}
 	esc := ctrl.New(esg)
-	ctr := adctrl.New(os.Stdin, esc, g)
+	ctr := adctrl.NewWithPrompter(GetPrompter(), esc, g)
 	return handler.New(ctr), nil
 }
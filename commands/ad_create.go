@@ -27,6 +27,7 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	handler "opensearch-cli/handler/ad"
 
 	"github.com/spf13/cobra"
@@ -47,7 +48,8 @@ var createCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		generate, _ := cmd.Flags().GetBool(generate)
 		if generate {
-			generateTemplate()
+			err := generateTemplate(cmd.OutOrStdout())
+			DisplayError(err, createDetectorsCommandName)
 			return
 		}
 		//If no args, display usage
@@ -60,10 +62,13 @@ var createCmd = &cobra.Command{
 	},
 }
 
-//generateTemplate prints sample detector configuration
-func generateTemplate() {
-	detector, _ := handler.GenerateAnomalyDetector()
-	fmt.Println(string(detector))
+//generateTemplate prints sample detector configuration in the format requested by --output flag
+func generateTemplate(w io.Writer) error {
+	detector, err := handler.GenerateAnomalyDetector()
+	if err != nil {
+		return err
+	}
+	return renderOutput(w, detector, OutputJSON)
 }
 
 func init() {
@@ -26,8 +26,6 @@
 package commands
 
 import (
-	"encoding/json"
-	"fmt"
 	"io"
 	entity "opensearch-cli/entity/ad"
 	"opensearch-cli/handler/ad"
@@ -103,11 +101,19 @@ func getDetectorsByID(commandHandler *ad.Handler, ID string) ([]*entity.Detector
 	return []*entity.DetectorOutput{output}, nil
 }
 
-//fprint displays the list of detectors.
+//fprint displays the list of detectors. table and csv formats display all detectors together,
+//while other formats display one document per detector
 func fprint(cmd *cobra.Command, display Display, results []*entity.DetectorOutput) error {
 	if results == nil {
 		return nil
 	}
+	format, err := getOutputFormat(OutputJSON)
+	if err != nil {
+		return err
+	}
+	if format == OutputTable || format == OutputCSV {
+		return Render(cmd.OutOrStdout(), format, results)
+	}
 	for _, d := range results {
 		if err := display(cmd, d); err != nil {
 			return err
@@ -117,14 +123,9 @@ func fprint(cmd *cobra.Command, display Display, results []*entity.DetectorOutpu
 }
 
 //FPrint prints detector configuration on writer
-//Since this is json format, use indent function to pretty print before printing on writer
+//Default format is json, since detector configuration downloaded by get is the input for update
 func FPrint(writer io.Writer, d *entity.DetectorOutput) error {
-	formattedOutput, err := json.MarshalIndent(d, "", "  ")
-	if err != nil {
-		return err
-	}
-	_, err = fmt.Fprintln(writer, string(formattedOutput))
-	return err
+	return renderOutput(writer, d, OutputJSON)
 }
 
 //Println prints detector configuration on stdout
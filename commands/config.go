@@ -0,0 +1,227 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"opensearch-cli/controller/config"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	ConfigCommandName        = "config"
+	MigrateConfigCommandName = "migrate"
+	TrustConfigCommandName   = "trust"
+	FlagConfigDryRun         = "dry-run"
+)
+
+//configCommand is main command for config file operations
+var configCommand = &cobra.Command{
+	Use:   ConfigCommandName + " sub-command",
+	Short: "Manage the opensearch-cli config file",
+	Long: "Manage the opensearch-cli config file. Config files created by older opensearch-cli are upgraded automatically " +
+		"when they are changed, use `migrate` to upgrade config file explicitly. Project config file is used only after " +
+		"it is trusted by `trust`.",
+}
+
+//migrateConfigCmd upgrades config file to the version supported by this opensearch-cli
+var migrateConfigCmd = &cobra.Command{
+	Use:   MigrateConfigCommandName,
+	Short: "Upgrade config file to the latest version",
+	Long: "Upgrade config file to the version supported by this opensearch-cli. Original config file is saved in the same " +
+		"folder with the version as suffix, for example, config.yaml.v0.bak. Use --dry-run to show changes without updating config file.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool(FlagConfigDryRun)
+		if err := migrateConfig(os.Stdout, dryRun); err != nil {
+			DisplayError(err, MigrateConfigCommandName)
+		}
+	},
+}
+
+//trustConfigCmd trusts project config file, so that it is overlaid on config file
+var trustConfigCmd = &cobra.Command{
+	Use:   TrustConfigCommandName + " [" + ProjectConfigFileName + "]",
+	Short: "Trust project config file",
+	Long: "Trust project config file, by default the nearest " + ProjectConfigFileName + " in current directory or its parents. " +
+		"Project config file can run commands and send credentials of your profiles to its endpoints, hence, it is used only " +
+		"after it is trusted, and it has to be trusted again whenever it is changed.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var projectFilePath string
+		if len(args) > 0 {
+			projectFilePath = args[0]
+		}
+		if err := trustProjectConfig(os.Stdout, projectFilePath); err != nil {
+			DisplayError(err, TrustConfigCommandName)
+		}
+	},
+}
+
+func init() {
+	configCommand.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+ConfigCommandName)
+	migrateConfigCmd.Flags().Bool(FlagConfigDryRun, false, "Show changes without updating config file")
+	migrateConfigCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+MigrateConfigCommandName)
+	configCommand.AddCommand(migrateConfigCmd)
+	trustConfigCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+TrustConfigCommandName)
+	configCommand.AddCommand(trustConfigCmd)
+	GetRoot().AddCommand(configCommand)
+}
+
+//migrateConfig upgrades config file, if dryRun is true, prints difference between current and upgraded config file
+func migrateConfig(w io.Writer, dryRun bool) (err error) {
+	cfgFile, err := GetRoot().Flags().GetString(flagConfig)
+	if err != nil {
+		return err
+	}
+	configController, err := getConfigController(cfgFile)
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		unlock, err := configController.Lock()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if unlockErr := unlock(); err == nil {
+				err = unlockErr
+			}
+		}()
+	}
+	migration, err := configController.Migrate(true)
+	if err != nil {
+		return err
+	}
+	if !dryRun && migration.IsRequired() {
+		proceed, err := GetPrompter().Confirm(fmt.Sprintf(
+			"opensearch-cli will migrate config file from version %d to %d. Do you want to proceed? Y/N ",
+			migration.FromVersion, migration.ToVersion))
+		if err != nil || !proceed {
+			return err
+		}
+		if migration, err = configController.Migrate(false); err != nil {
+			return err
+		}
+	}
+	if !migration.IsRequired() {
+		_, err = fmt.Fprintf(w, "Config file is already at version %d.\n", migration.ToVersion)
+		return err
+	}
+	if !dryRun {
+		_, err = fmt.Fprintf(w, "Config file is migrated from version %d to %d, original config file is saved at %s.\n",
+			migration.FromVersion, migration.ToVersion, migration.BackupPath)
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "Config file will be migrated from version %d to %d:\n", migration.FromVersion, migration.ToVersion); err != nil {
+		return err
+	}
+	for _, line := range diffLines(string(migration.Original), string(migration.Migrated)) {
+		if _, err = fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//trustProjectConfig prints project config file and trusts it once user confirms, nearest project config file
+//is trusted if path is empty
+func trustProjectConfig(w io.Writer, path string) error {
+	if len(path) == 0 {
+		var ok bool
+		if path, ok = findProjectConfigFile(); !ok {
+			return fmt.Errorf("project config file %s is not found in current directory or its parents", ProjectConfigFileName)
+		}
+	}
+	cfgFile, err := GetRoot().Flags().GetString(flagConfig)
+	if err != nil {
+		return err
+	}
+	configFilePath, err := GetConfigFilePath(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to get config file due to: %w", err)
+	}
+	//contents are read once, so that the contents shown to user are trusted
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	trusted, err := config.IsProjectTrusted(configFilePath, path, contents)
+	if err != nil {
+		return err
+	}
+	if trusted {
+		_, err = fmt.Fprintf(w, "Project config file %s is already trusted.\n", path)
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "%s:\n%s\n", path, strings.TrimSuffix(string(contents), "\n")); err != nil {
+		return err
+	}
+	proceed, err := GetPrompter().Confirm(fmt.Sprintf("opensearch-cli will trust project config file %s, "+
+		"which can run commands and send credentials of your profiles to its endpoints. Do you want to proceed? Y/N ", path))
+	if err != nil || !proceed {
+		return err
+	}
+	if err = config.TrustProject(configFilePath, path, contents); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Project config file %s is trusted.\n", path)
+	return err
+}
+
+//diffLines compares lines of before and after, and returns every line prefixed by "-" if it is removed,
+//"+" if it is added and " " if it is unchanged
+func diffLines(before string, after string) []string {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+	//common[i][j] is the length of longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+	var result []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, " "+a[i])
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			result = append(result, "-"+a[i])
+			i++
+		default:
+			result = append(result, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		result = append(result, "+"+b[j])
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"opensearch-cli/controller/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	t.Run("added and removed lines", func(t *testing.T) {
+		before := "profiles:\n  - name: default\n    user: admin\n"
+		after := "version: 1\nprofiles:\n  - name: default\n    username: admin\n"
+		assert.EqualValues(t, []string{
+			"+version: 1",
+			" profiles:",
+			"   - name: default",
+			"-    user: admin",
+			"+    username: admin",
+		}, diffLines(before, after))
+	})
+	t.Run("same lines", func(t *testing.T) {
+		assert.EqualValues(t, []string{" profiles: []"}, diffLines("profiles: []\n", "profiles: []\n"))
+	})
+}
+
+//setPromptFlag enables --yes or --no-input flag until test is finished
+func setPromptFlag(t *testing.T, name string) {
+	assert.NoError(t, rootCommand.PersistentFlags().Set(name, "true"))
+	t.Cleanup(func() {
+		assert.NoError(t, rootCommand.PersistentFlags().Set(name, "false"))
+		rootCommand.PersistentFlags().Lookup(name).Changed = false
+	})
+}
+
+func TestMigrateConfig(t *testing.T) {
+	original := "profiles:\n    - name: default\n      endpoint: https://localhost:9200\n"
+	f, err := ioutil.TempFile("", "config")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer func() {
+		assert.NoError(t, os.Remove(f.Name()))
+		assert.NoError(t, os.Remove(f.Name()+".v0.bak"))
+	}()
+	assert.NoError(t, ioutil.WriteFile(f.Name(), []byte(original), FilePermission))
+	root := GetRoot()
+	root.SetArgs([]string{"--config", f.Name()})
+	_, err = root.ExecuteC()
+	assert.NoError(t, err)
+	t.Run("dry run", func(t *testing.T) {
+		var output bytes.Buffer
+		assert.NoError(t, migrateConfig(&output, true))
+		assert.EqualValues(t, "Config file will be migrated from version 0 to 1:\n"+
+			"+version: 1\n"+
+			" profiles:\n"+
+			"     - name: default\n"+
+			"       endpoint: https://localhost:9200\n", output.String())
+		contents, err := ioutil.ReadFile(f.Name())
+		assert.NoError(t, err)
+		assert.EqualValues(t, original, string(contents))
+	})
+	t.Run("confirmation is required", func(t *testing.T) {
+		setPromptFlag(t, flagNoInput)
+		var output bytes.Buffer
+		assert.EqualError(t, migrateConfig(&output, false), "user input is required for "+
+			"'opensearch-cli will migrate config file from version 0 to 1. Do you want to proceed? Y/N', but prompts are disabled")
+		contents, err := ioutil.ReadFile(f.Name())
+		assert.NoError(t, err)
+		assert.EqualValues(t, original, string(contents))
+	})
+	t.Run("migrate", func(t *testing.T) {
+		setPromptFlag(t, flagAssumeYes)
+		var output bytes.Buffer
+		assert.NoError(t, migrateConfig(&output, false))
+		assert.EqualValues(t, "Config file is migrated from version 0 to 1, original config file is saved at "+f.Name()+".v0.bak.\n", output.String())
+		output.Reset()
+		assert.NoError(t, migrateConfig(&output, false))
+		assert.EqualValues(t, "Config file is already at version 1.\n", output.String())
+	})
+}
+
+func TestTrustProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte("profiles: []\n"), FilePermission))
+	projectPath := filepath.Join(dir, ProjectConfigFileName)
+	project := "profiles:\n- name: default\n  endpoint: https://project:9200\n"
+	assert.NoError(t, ioutil.WriteFile(projectPath, []byte(project), FilePermission))
+	root := GetRoot()
+	root.SetArgs([]string{"--config", configPath})
+	_, err := root.ExecuteC()
+	assert.NoError(t, err)
+	t.Run("confirmation is required", func(t *testing.T) {
+		setPromptFlag(t, flagNoInput)
+		var output bytes.Buffer
+		assert.EqualError(t, trustProjectConfig(&output, projectPath), "user input is required for "+
+			"'opensearch-cli will trust project config file "+projectPath+", which can run commands and send credentials "+
+			"of your profiles to its endpoints. Do you want to proceed? Y/N', but prompts are disabled")
+		assert.EqualValues(t, projectPath+":\n"+strings.TrimSuffix(project, "\n")+"\n", output.String())
+		trusted, err := config.IsProjectTrusted(configPath, projectPath, []byte(project))
+		assert.NoError(t, err)
+		assert.False(t, trusted)
+	})
+	t.Run("trust", func(t *testing.T) {
+		setPromptFlag(t, flagAssumeYes)
+		var output bytes.Buffer
+		assert.NoError(t, trustProjectConfig(&output, projectPath))
+		assert.Contains(t, output.String(), "Project config file "+projectPath+" is trusted.\n")
+		trusted, err := config.IsProjectTrusted(configPath, projectPath, []byte(project))
+		assert.NoError(t, err)
+		assert.True(t, trusted)
+
+		output.Reset()
+		assert.NoError(t, trustProjectConfig(&output, projectPath))
+		assert.EqualValues(t, "Project config file "+projectPath+" is already trusted.\n", output.String())
+	})
+}
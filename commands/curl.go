@@ -26,12 +26,11 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
-	"opensearch-cli/client"
-	ctrl "opensearch-cli/controller/platform"
 	entity "opensearch-cli/entity/platform"
-	gateway "opensearch-cli/gateway/platform"
 	handler "opensearch-cli/handler/platform"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -72,20 +71,11 @@ func GetCurlCommand() *cobra.Command {
 
 //getCurlHandler returns handler by wiring the dependency manually
 func getCurlHandler() (*handler.Handler, error) {
-	c, err := client.New(nil)
-	if err != nil {
-		return nil, err
-	}
 	profile, err := GetProfile()
 	if err != nil {
 		return nil, err
 	}
-	g, err := gateway.New(c, profile)
-	if err != nil {
-		return nil, err
-	}
-	facade := ctrl.New(g)
-	return handler.New(facade), nil
+	return getPlatformHandler(profile)
 }
 
 //CurlActionExecute executes API based on user request
@@ -97,16 +87,27 @@ func CurlActionExecute(input entity.CurlCommandRequest) error {
 	}
 	response, err := handler.Curl(commandHandler, input)
 	if err == nil {
-		fmt.Println(string(response))
-		return nil
+		return printCurlResponse(response)
 	}
-	if requestError, ok := err.(*entity.RequestError); ok {
+	if requestError, ok := err.(*entity.RequestError); ok && !isJSONErrorFormat() {
 		fmt.Println(requestError.GetResponse())
-		return nil
 	}
 	return err
 }
 
+//printCurlResponse prints response as it is received from cluster, unless user requested
+//specific format using --output flag
+func printCurlResponse(response []byte) error {
+	if !isOutputFormatSet() {
+		fmt.Println(string(response))
+		return nil
+	}
+	if !json.Valid(response) {
+		return fmt.Errorf("response cannot be rendered using --%s since it is not a json document", flagOutput)
+	}
+	return renderOutput(os.Stdout, json.RawMessage(response), OutputJSON)
+}
+
 func FormatOutput() bool {
 	isPretty, _ := curlCommand.PersistentFlags().GetBool(curlPrettyFlagName)
 	return isPretty
@@ -0,0 +1,179 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"opensearch-cli/client"
+	"opensearch-cli/entity/ad"
+	"opensearch-cli/entity/platform"
+	"opensearch-cli/gateway/aws/signer"
+	"os"
+)
+
+//Exit codes returned by opensearch-cli, scripts can use them to decide how to proceed on failure
+const (
+	ExitCodeOK             = 0
+	ExitCodeGeneralError   = 1
+	ExitCodeUsageError     = 2
+	ExitCodeAuthError      = 3
+	ExitCodeClientError    = 4
+	ExitCodeServerError    = 5
+	ExitCodeNetworkError   = 6
+	ExitCodePartialFailure = 7
+)
+
+const (
+	flagErrorFormat = "error-format"
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+//UsageError represents failure due to invalid command, arguments or flags
+type UsageError struct {
+	err error
+}
+
+//Error inherits error interface to pass as error
+func (u *UsageError) Error() string {
+	return u.err.Error()
+}
+
+//Unwrap returns underlying error
+func (u *UsageError) Unwrap() error {
+	return u.err
+}
+
+//commandError holds the error reported by DisplayError during current execution,
+//since commands handle their own error message, this is used only to decide exit code
+var commandError error
+
+//errorEnvelope represents error written on stderr if --error-format is json
+type errorEnvelope struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Command    string `json:"command,omitempty"`
+	Message    string `json:"message"`
+	ExitCode   int    `json:"exit_code"`
+	StatusCode int    `json:"status_code,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+//ExitCode maps error to exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	var usageError *UsageError
+	if errors.As(err, &usageError) {
+		return ExitCodeUsageError
+	}
+	var partialFailure *ad.PartialFailureError
+	if errors.As(err, &partialFailure) {
+		return ExitCodePartialFailure
+	}
+	var requestError *platform.RequestError
+	if errors.As(err, &requestError) {
+		switch {
+		case requestError.StatusCode() == http.StatusUnauthorized || requestError.StatusCode() == http.StatusForbidden:
+			return ExitCodeAuthError
+		case requestError.StatusCode() < http.StatusInternalServerError:
+			return ExitCodeClientError
+		default:
+			return ExitCodeServerError
+		}
+	}
+	var unsupportedError *signer.UnsupportedOperationError
+	if errors.As(err, &unsupportedError) {
+		return ExitCodeClientError
+	}
+	//replayer doesn't connect to cluster, though error is reported as url.Error
+	var notRecorded *client.NotRecordedError
+	if errors.As(err, &notRecorded) {
+		return ExitCodeGeneralError
+	}
+	if isNetworkError(err) {
+		return ExitCodeNetworkError
+	}
+	return ExitCodeGeneralError
+}
+
+//isNetworkError checks whether error is caused by connection failure or timeout
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+	var urlError *url.Error
+	return errors.As(err, &urlError)
+}
+
+//newErrorEnvelope builds error envelope with details from cluster's response if available
+func newErrorEnvelope(err error, cmdName string) errorEnvelope {
+	detail := errorDetail{
+		Command:  cmdName,
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+	}
+	var requestError *platform.RequestError
+	if errors.As(err, &requestError) {
+		detail.StatusCode = requestError.StatusCode()
+		detail.URL = requestError.URL()
+		detail.Type = requestError.ErrorType()
+		detail.Reason = requestError.ErrorReason()
+	}
+	return errorEnvelope{Error: detail}
+}
+
+//printErrorEnvelope writes error envelope as json on writer
+func printErrorEnvelope(w io.Writer, err error, cmdName string) {
+	contents, marshalErr := json.Marshal(newErrorEnvelope(err, cmdName))
+	if marshalErr != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, string(contents))
+}
+
+//isJSONErrorFormat returns true if user requested errors as json
+func isJSONErrorFormat() bool {
+	format, _ := rootCommand.PersistentFlags().GetString(flagErrorFormat)
+	return format == errorFormatJSON
+}
+
+//validateErrorFormat checks whether given error format is supported
+func validateErrorFormat(format string) error {
+	if format != errorFormatText && format != errorFormatJSON {
+		return fmt.Errorf("invalid value '%s' for --%s. Options are %s, %s", format, flagErrorFormat, errorFormatText, errorFormatJSON)
+	}
+	return nil
+}
+
+//reportUsageError writes error envelope for errors raised by command parsing
+func reportUsageError(err error) error {
+	usageError := &UsageError{err}
+	if isJSONErrorFormat() {
+		printErrorEnvelope(os.Stderr, usageError, "")
+	}
+	return usageError
+}
@@ -0,0 +1,83 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"opensearch-cli/client"
+	"opensearch-cli/entity/ad"
+	"opensearch-cli/entity/platform"
+	"opensearch-cli/gateway/aws/signer"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeRequestError(statusCode int, body string) *platform.RequestError {
+	return platform.NewRequestError(
+		statusCode,
+		"https://localhost:9200/my-index",
+		ioutil.NopCloser(strings.NewReader(body)),
+		fmt.Errorf("%d Client Error", statusCode))
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"no error", nil, ExitCodeOK},
+		{"general error", errors.New("failed"), ExitCodeGeneralError},
+		{"usage error", &UsageError{errors.New("unknown flag: --foo")}, ExitCodeUsageError},
+		{"unauthorized", fakeRequestError(401, ""), ExitCodeAuthError},
+		{"forbidden", fakeRequestError(403, ""), ExitCodeAuthError},
+		{"not found", fakeRequestError(404, ""), ExitCodeClientError},
+		{"not found from response error", platform.NewResponseError(fakeRequestError(404, "")), ExitCodeClientError},
+		{"server error", fakeRequestError(503, ""), ExitCodeServerError},
+		{"timeout", fmt.Errorf("failed: %w", context.DeadlineExceeded), ExitCodeNetworkError},
+		{"connection refused", &url.Error{Op: "Get", URL: "https://localhost:9200", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, ExitCodeNetworkError},
+		{"partial failure", &ad.PartialFailureError{Action: "start", Total: 2, Failed: []string{"detector"}}, ExitCodePartialFailure},
+		{"not recorded", &url.Error{Op: "Get", URL: "https://localhost:9200", Err: &client.NotRecordedError{Dir: "fixtures", Method: "GET", URI: "/"}}, ExitCodeGeneralError},
+		{"unsupported by serverless", fmt.Errorf("failed: %w", &signer.UnsupportedOperationError{Service: "aoss", Path: "/_cluster/health"}), ExitCodeClientError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualValues(t, tc.expected, ExitCode(tc.err))
+		})
+	}
+}
+
+func TestPrintErrorEnvelope(t *testing.T) {
+	t.Run("request error", func(t *testing.T) {
+		var out bytes.Buffer
+		err := fakeRequestError(404, `{"error":{"type":"index_not_found_exception","reason":"no such index [my-index]"},"status":404}`)
+		printErrorEnvelope(&out, err, "get")
+		assert.JSONEq(t, `{"error":{
+			"command":"get",
+			"message":"404 Client Error",
+			"exit_code":4,
+			"status_code":404,
+			"url":"https://localhost:9200/my-index",
+			"type":"index_not_found_exception",
+			"reason":"no such index [my-index]"}}`, out.String())
+	})
+	t.Run("general error", func(t *testing.T) {
+		var out bytes.Buffer
+		printErrorEnvelope(&out, errors.New("no profile found"), "get")
+		assert.JSONEq(t, `{"error":{"command":"get","message":"no profile found","exit_code":1}}`, out.String())
+	})
+}
@@ -0,0 +1,366 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	flagOutput  = "output"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+	OutputCSV   = "csv"
+)
+
+var outputFormats = []string{OutputJSON, OutputYAML, OutputTable, OutputCSV}
+
+//orderedObject represents json object which preserves the order of keys as received,
+//so that rendered output follows the same order as the source
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+//validateOutputFormat checks whether given format is supported by the renderer
+func validateOutputFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value '%s' for --%s. Options are %s", format, flagOutput, strings.Join(outputFormats, ", "))
+}
+
+//isOutputFormatSet returns true if user requested specific output format using --output flag
+func isOutputFormatSet() bool {
+	format, _ := rootCommand.PersistentFlags().GetString(flagOutput)
+	return format != ""
+}
+
+//getOutputFormat returns format requested by --output flag, if flag is not set, defaultFormat is returned
+func getOutputFormat(defaultFormat string) (string, error) {
+	format, err := rootCommand.PersistentFlags().GetString(flagOutput)
+	if err != nil {
+		return "", err
+	}
+	if format == "" {
+		return defaultFormat, nil
+	}
+	return format, validateOutputFormat(format)
+}
+
+//renderOutput writes value on writer in the format requested by --output flag,
+//if flag is not set, value is written in defaultFormat
+func renderOutput(w io.Writer, value interface{}, defaultFormat string) error {
+	format, err := getOutputFormat(defaultFormat)
+	if err != nil {
+		return err
+	}
+	return Render(w, format, value)
+}
+
+//Render writes value on writer in given format. value can be any type that can be marshalled
+//into json, []byte and json.RawMessage are expected to be valid json document.
+func Render(w io.Writer, format string, value interface{}) error {
+	contents, err := toJSON(value)
+	if err != nil {
+		return err
+	}
+	if format == OutputJSON {
+		var out bytes.Buffer
+		if err := json.Indent(&out, contents, "", "  "); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, out.String())
+		return err
+	}
+	document, err := decodeOrdered(contents)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case OutputYAML:
+		return renderYAML(w, document)
+	case OutputTable:
+		return renderTable(w, document)
+	case OutputCSV:
+		return renderCSV(w, document)
+	}
+	return validateOutputFormat(format)
+}
+
+//toJSON converts value into json document
+func toJSON(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case json.RawMessage:
+		return checkJSON(v)
+	case []byte:
+		return checkJSON(v)
+	}
+	return json.Marshal(value)
+}
+
+func checkJSON(contents []byte) ([]byte, error) {
+	if !json.Valid(contents) {
+		return nil, fmt.Errorf("response is not a valid json document")
+	}
+	return contents, nil
+}
+
+//decodeOrdered decodes json document into generic value where objects are represented by orderedObject
+func decodeOrdered(contents []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		object := &orderedObject{values: map[string]interface{}{}}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := object.values[key]; !exists {
+				object.keys = append(object.keys, key)
+			}
+			object.values[key] = value
+		}
+		_, err = decoder.Token() // consume '}'
+		return object, err
+	case '[':
+		values := []interface{}{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err = decoder.Token() // consume ']'
+		return values, err
+	}
+	return nil, fmt.Errorf("unexpected delimiter %s", delim)
+}
+
+func renderYAML(w io.Writer, document interface{}) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(toYAMLNode(document)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+//toYAMLNode builds yaml node from generic value, nodes are used instead of maps to preserve order of keys
+func toYAMLNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case *orderedObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range v.keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				toYAMLNode(v.values[key]))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			node.Content = append(node.Content, toYAMLNode(item))
+		}
+		return node
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+//toRows converts document into header and rows. List of objects is displayed as one row per object,
+//a single object is displayed as key value pairs, nested fields are flattened using '.' as separator.
+func toRows(document interface{}) ([]string, [][]string) {
+	switch v := document.(type) {
+	case []interface{}:
+		if !containsObjects(v) {
+			rows := make([][]string, 0, len(v))
+			for _, item := range v {
+				rows = append(rows, []string{formatCell(item)})
+			}
+			return []string{"value"}, rows
+		}
+		var header []string
+		seen := map[string]bool{}
+		var flattened []map[string]string
+		for _, item := range v {
+			fields := &orderedObject{values: map[string]interface{}{}}
+			flatten("", item, fields)
+			for _, key := range fields.keys {
+				if !seen[key] {
+					seen[key] = true
+					header = append(header, key)
+				}
+			}
+			row := map[string]string{}
+			for key, value := range fields.values {
+				row[key] = formatCell(value)
+			}
+			flattened = append(flattened, row)
+		}
+		rows := make([][]string, 0, len(flattened))
+		for _, item := range flattened {
+			row := make([]string, 0, len(header))
+			for _, key := range header {
+				row = append(row, item[key])
+			}
+			rows = append(rows, row)
+		}
+		return header, rows
+	case *orderedObject:
+		fields := &orderedObject{values: map[string]interface{}{}}
+		flatten("", v, fields)
+		rows := make([][]string, 0, len(fields.keys))
+		for _, key := range fields.keys {
+			rows = append(rows, []string{key, formatCell(fields.values[key])})
+		}
+		return []string{"key", "value"}, rows
+	}
+	return []string{"value"}, [][]string{{formatCell(document)}}
+}
+
+func containsObjects(values []interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.(*orderedObject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+//flatten adds leaf values of given value into result with dotted path as key,
+//list of scalar values are kept as it is to display them in single cell
+func flatten(prefix string, value interface{}, result *orderedObject) {
+	add := func(key string, value interface{}) {
+		if _, exists := result.values[key]; !exists {
+			result.keys = append(result.keys, key)
+		}
+		result.values[key] = value
+	}
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := value.(type) {
+	case *orderedObject:
+		if len(v.keys) == 0 && prefix != "" {
+			add(prefix, nil)
+		}
+		for _, key := range v.keys {
+			flatten(join(key), v.values[key], result)
+		}
+	case []interface{}:
+		if !containsObjects(v) {
+			add(prefix, v)
+			return
+		}
+		for i, item := range v {
+			flatten(join(strconv.Itoa(i)), item, result)
+		}
+	default:
+		add(prefix, v)
+	}
+}
+
+//formatCell converts leaf value into string
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatCell(item))
+		}
+		return strings.Join(items, ",")
+	}
+	contents, _ := json.Marshal(value)
+	return string(contents)
+}
+
+//renderTable displays document as table, header is followed by an underline similar to profile list
+func renderTable(w io.Writer, document interface{}) (err error) {
+	header, rows := toRows(document)
+	writer := tabwriter.NewWriter(w, 0, 0, padding, ' ', alignLeft)
+	underline := make([]string, 0, len(header))
+	for i, column := range header {
+		header[i] = strings.ToUpper(column)
+		underline = append(underline, strings.Repeat("-", len(column)))
+	}
+	for _, row := range append([][]string{header, underline}, rows...) {
+		if _, err = fmt.Fprintln(writer, strings.Join(row, "\t")+"\t"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func renderCSV(w io.Writer, document interface{}) error {
+	header, rows := toRows(document)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
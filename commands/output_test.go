@@ -0,0 +1,85 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	profiles := []profileSummary{
+		{Name: "default", UserName: "admin", Endpoint: "https://localhost:9200"},
+		{Name: "dev", Endpoint: "https://127.0.0.1:9200"},
+	}
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, Render(&out, OutputJSON, profiles[1]))
+		assert.EqualValues(t, "{\n  \"name\": \"dev\",\n  \"user\": \"\",\n  \"endpoint\": \"https://127.0.0.1:9200\"\n}\n", out.String())
+	})
+	t.Run("yaml preserves order of keys", func(t *testing.T) {
+		var out bytes.Buffer
+		response := json.RawMessage(`{"name":"node-1","count":1612345678901,"ratio":0.5,"enabled":"true","roles":["data","ingest"],"meta":null}`)
+		assert.NoError(t, Render(&out, OutputYAML, response))
+		assert.EqualValues(t, "name: node-1\ncount: 1612345678901\nratio: 0.5\nenabled: \"true\"\nroles:\n  - data\n  - ingest\nmeta: null\n", out.String())
+	})
+	t.Run("table for list of objects", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, Render(&out, OutputTable, profiles))
+		expected := "NAME      USER    ENDPOINT                 \n" +
+			"----      ----    --------                 \n" +
+			"default   admin   https://localhost:9200   \n" +
+			"dev               https://127.0.0.1:9200   \n"
+		assert.EqualValues(t, expected, out.String())
+	})
+	t.Run("table for nested object", func(t *testing.T) {
+		var out bytes.Buffer
+		response := json.RawMessage(`{"_shards":{"total":2,"failed":0},"indices":["a","b"]}`)
+		assert.NoError(t, Render(&out, OutputTable, response))
+		expected := "KEY              VALUE   \n" +
+			"---              -----   \n" +
+			"_shards.total    2       \n" +
+			"_shards.failed   0       \n" +
+			"indices          a,b     \n"
+		assert.EqualValues(t, expected, out.String())
+	})
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, Render(&out, OutputCSV, profiles))
+		assert.EqualValues(t, "name,user,endpoint\ndefault,admin,https://localhost:9200\ndev,,https://127.0.0.1:9200\n", out.String())
+	})
+	t.Run("csv for list of values", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, Render(&out, OutputCSV, []string{"default", "dev"}))
+		assert.EqualValues(t, "value\ndefault\ndev\n", out.String())
+	})
+	t.Run("invalid json document", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Render(&out, OutputYAML, []byte("green open index"))
+		assert.EqualError(t, err, "response is not a valid json document")
+	})
+	t.Run("invalid format", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Render(&out, "xml", profiles)
+		assert.EqualError(t, err, "invalid value 'xml' for --output. Options are json, yaml, table, csv")
+	})
+}
+
+func TestGenerateTemplateOutput(t *testing.T) {
+	defer func() {
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagOutput, ""))
+	}()
+	assert.NoError(t, rootCommand.PersistentFlags().Set(flagOutput, OutputYAML))
+	var out bytes.Buffer
+	assert.NoError(t, generateTemplate(&out))
+	assert.Contains(t, out.String(), "name: Detector Name\n")
+}
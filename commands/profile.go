@@ -29,15 +29,13 @@ import (
 	"errors"
 	"fmt"
 	"opensearch-cli/environment"
-
-	"golang.org/x/term"
+	"opensearch-cli/prompt"
 
 	"opensearch-cli/controller/config"
 	"opensearch-cli/controller/profile"
 	"opensearch-cli/entity"
+	"opensearch-cli/gateway/aws/signer"
 	"os"
-	"strings"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
@@ -103,17 +101,22 @@ var createProfileCmd = &cobra.Command{
 			MaxRetry: &maxAttempt,
 			Timeout:  &timeout,
 		}
-		switch authType, _ := cmd.Flags().GetString(FlagProfileCreateAuthType); authType {
-		case "disabled":
-			break
-		case "basic":
-			getBasicAuthDetails(&newProfile)
-		case "aws-iam":
-			getAWSIAMAuthDetails(&newProfile)
-		case "cert":
-			getCertificateAuthDetails(&newProfile)
-		default:
-			DisplayError(errors.New("invalid value for auth-type. Use --help -h command to see permitted values"), CreateNewProfileCommandName)
+		if err = applyEndpointFlags(cmd, &newProfile); err != nil {
+			DisplayError(err, CreateNewProfileCommandName)
+			return
+		}
+		applyRetryFlags(cmd, &newProfile)
+		if err = validateRetry(newProfile.Retry); err != nil {
+			DisplayError(err, CreateNewProfileCommandName)
+			return
+		}
+		authType, _ := cmd.Flags().GetString(FlagProfileCreateAuthType)
+		if err = getAuthDetails(GetPrompter(), authType, &newProfile); err != nil {
+			DisplayError(err, CreateNewProfileCommandName)
+			return
+		}
+		if err = applyTLSFlags(cmd, &newProfile); err != nil {
+			DisplayError(err, CreateNewProfileCommandName)
 			return
 		}
 		err = CreateProfile(profileController, newProfile)
@@ -125,6 +128,27 @@ var createProfileCmd = &cobra.Command{
 	},
 }
 
+//getAuthDetails prompts for authentication details required by given auth type
+func getAuthDetails(p *prompt.Prompter, authType string, newProfile *entity.Profile) error {
+	switch authType {
+	case "disabled":
+		return nil
+	case "basic":
+		return getBasicAuthDetails(p, newProfile)
+	case "aws-iam":
+		return getAWSIAMAuthDetails(p, newProfile)
+	case "cert":
+		return getCertificateAuthDetails(p, newProfile)
+	case "token":
+		return getTokenAuthDetails(p, newProfile)
+	case "api-key":
+		return getAPIKeyAuthDetails(p, newProfile)
+	case "credential-process":
+		return getCredentialProcessDetails(p, newProfile)
+	}
+	return errors.New("invalid value for auth-type. Use --help -h command to see permitted values")
+}
+
 func getProfileName(cmd *cobra.Command, controller profile.Controller) (string, error) {
 	name, _ := cmd.Flags().GetString(FlagProfileCreateName)
 	if err := validateProfileName(name, controller); err != nil {
@@ -188,15 +212,21 @@ func init() {
 	_ = createProfileCmd.MarkFlagRequired(FlagProfileCreateName)
 	createProfileCmd.Flags().StringP(FlagProfileCreateEndpoint, "e", "", "Create profile with this endpoint or host")
 	_ = createProfileCmd.MarkFlagRequired(FlagProfileCreateEndpoint)
-	createProfileCmd.Flags().StringP(FlagProfileCreateAuthType, "a", "", "Authentication type. Options are disabled, basic and aws-iam."+
+	createProfileCmd.Flags().StringP(FlagProfileCreateAuthType, "a", "", "Authentication type. Options are disabled, basic, cert, token, api-key, credential-process and aws-iam."+
 		"\nIf security is disabled, provide --auth-type='disabled'.\nIf security uses HTTP basic authentication, provide --auth-type='basic'.\n"+
 		"If security uses client certificate authentication, provide --auth-type='cert'.\n"+
+		"If security uses bearer tokens like JWT or OpenID Connect, provide --auth-type='token'.\n"+
+		"If security uses API keys, provide --auth-type='api-key'.\n"+
+		"If credentials should be read from a command like your vault tooling, provide --auth-type='credential-process'.\n"+
 		"If security uses AWS IAM ARNs as users, provide --auth-type='aws-iam'.\nopensearch-cli asks for additional information based on your choice of authentication type.")
 	_ = createProfileCmd.MarkFlagRequired(FlagProfileCreateAuthType)
 	createProfileCmd.Flags().IntP(FlagProfileMaxRetry, "m", 3, "Maximum retry attempts allowed if transient problems occur.\n"+
 		"You can override this value by using the "+environment.OPENSEARCH_MAX_RETRY+" environment variable.")
 	createProfileCmd.Flags().Int64P(FlagProfileTimeout, "t", 10, "Maximum time allowed for connection in seconds.\n"+
 		"You can override this value by using the "+environment.OPENSEARCH_TIMEOUT+" environment variable.")
+	addTLSFlags(createProfileCmd)
+	addEndpointFlags(createProfileCmd)
+	addRetryFlags(createProfileCmd)
 	createProfileCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+CreateNewProfileCommandName)
 
 	//profile delete flags
@@ -207,6 +237,22 @@ func init() {
 
 //getProfileController gets profile controller by wiring config controller with config file
 func getProfileController(cfgFlagValue string) (profile.Controller, error) {
+	configController, err := getConfigController(cfgFlagValue)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := config.CipherFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	profileController := profile.NewWithCipher(configController, cipher)
+	return profileController, nil
+}
+
+//getConfigController gets config controller for config file, config file should be accessible only by owner.
+//Unless config file is provided by flag, project config file found in current directory or its parents
+//is overlaid on config file
+func getConfigController(cfgFlagValue string) (config.Controller, error) {
 	configFilePath, err := GetConfigFilePath(cfgFlagValue)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config file due to: %w", err)
@@ -221,9 +267,13 @@ func getProfileController(cfgFlagValue string) (profile.Controller, error) {
 	if mode != FilePermission {
 		return nil, fmt.Errorf("permissions %o for '%s' are too open. It is required that your config file is NOT accessible by others", mode, configFilePath)
 	}
-	configController := config.New(configFilePath)
-	profileController := profile.New(configController)
-	return profileController, nil
+	if len(cfgFlagValue) > 0 {
+		return config.New(configFilePath), nil
+	}
+	if projectFilePath, ok := findProjectConfigFile(); ok && !isSameFile(projectFilePath, configFilePath) {
+		return config.NewWithOverlay(configFilePath, projectFilePath), nil
+	}
+	return config.New(configFilePath), nil
 }
 
 // CreateProfile creates a new named profile
@@ -246,50 +296,96 @@ func validateProfileName(name string, controller profile.Controller) error {
 }
 
 // getBasicAuthDetails gets new basic HTTP Auth profile information from user using command line
-func getBasicAuthDetails(newProfile *entity.Profile) {
-	fmt.Printf("Username: ")
-	newProfile.UserName = getUserInputAsText(checkInputIsNotEmpty)
-	fmt.Printf("Password: ")
-	newProfile.Password = getUserInputAsMaskedText(checkInputIsNotEmpty)
+func getBasicAuthDetails(p *prompt.Prompter, newProfile *entity.Profile) (err error) {
+	if newProfile.UserName, err = p.Text("Username", checkInputIsNotEmpty); err != nil {
+		return err
+	}
+	newProfile.Password, err = p.MaskedText("Password", checkInputIsNotEmpty)
+	return err
 }
 
 // getAWSIAMAuthDetails gets new AWS IAM Auth profile information from user using command line
-func getAWSIAMAuthDetails(newProfile *entity.Profile) {
-	fmt.Printf("AWS profile name (leave blank if you want to provide credentials using environment variables): ")
+func getAWSIAMAuthDetails(p *prompt.Prompter, newProfile *entity.Profile) (err error) {
 	awsIAM := &entity.AWSIAM{}
-	awsIAM.ProfileName = getUserInputAsText(nil)
-	fmt.Printf("AWS service name where your cluster is deployed (for Amazon Elasticsearch Service, use 'es'. For EC2, use 'ec2'): ")
-	awsIAM.ServiceName = getUserInputAsText(checkInputIsNotEmpty)
+	if awsIAM.ProfileName, err = p.Text("AWS profile name (leave blank if you want to provide credentials using environment variables)", nil); err != nil {
+		return err
+	}
+	if awsIAM.ServiceName, err = p.Text("AWS service name where your cluster is deployed (for Amazon OpenSearch Service, use 'es'. For Amazon OpenSearch Serverless, use 'aoss')", checkAWSServiceName(p)); err != nil {
+		return err
+	}
+	if awsIAM.Region, err = p.Text("AWS region (leave blank to use region of AWS profile or 'AWS_REGION')", nil); err != nil {
+		return err
+	}
+	if awsIAM.RoleARN, err = p.Text("ARN of IAM role to assume (leave blank if not required)", nil); err != nil {
+		return err
+	}
+	if len(awsIAM.RoleARN) > 0 {
+		if awsIAM.ExternalID, err = p.Text("External ID required by the role (leave blank if not required)", nil); err != nil {
+			return err
+		}
+		if awsIAM.WebIdentityTokenFile, err = p.Text("Web identity token file (leave blank to assume role using AWS profile credentials)", nil); err != nil {
+			return err
+		}
+	}
 	newProfile.AWS = awsIAM
+	return nil
 }
 
 // getCertificateAuthDetails gets certificate and key paths profile information from user using command line
-func getCertificateAuthDetails(newProfile *entity.Profile) {
+func getCertificateAuthDetails(p *prompt.Prompter, newProfile *entity.Profile) error {
 	certificate := &entity.Trust{}
-	fmt.Printf("Certificate file path (leave blank if N/A): ")
-	if val := getUserInputAsText(nil); len(val) > 0 {
+	if newProfile.Certificate != nil {
+		*certificate = *newProfile.Certificate
+	}
+	val, err := p.Text("Certificate file path (leave blank if N/A)", nil)
+	if err != nil {
+		return err
+	}
+	if len(val) > 0 {
 		certificate.ClientCertificateFilePath = &val
-		fmt.Printf("Key file path: ")
-		if val := getUserInputAsText(checkInputIsNotEmpty); len(val) > 0 {
-			certificate.ClientKeyFilePath = &val
+		key, err := p.Text("Key file path", checkInputIsNotEmpty)
+		if err != nil {
+			return err
 		}
+		certificate.ClientKeyFilePath = &key
+	}
+	ca, err := p.Text("Certificate Authroity's (CA) certificate file path (leave blank if N/A)", nil)
+	if err != nil {
+		return err
 	}
-	fmt.Printf("Certificate Authroity's (CA) certificate file path (leave blank if N/A): ")
-	if val := getUserInputAsText(nil); len(val) > 0 {
-		certificate.CAFilePath = &val
+	if len(ca) > 0 {
+		certificate.CAFilePath = &ca
 	}
 	newProfile.Certificate = certificate
+	return nil
 }
 
-// getUserInputAsText get value from user as text
-func getUserInputAsText(isValid func(string) bool) string {
-	var response string
-	//Ignore return value since validation is applied below
-	_, _ = fmt.Scanln(&response)
-	if isValid != nil && !isValid(response) {
-		return getUserInputAsText(isValid)
+// getTokenAuthDetails gets bearer token or command which prints bearer token from user using command line
+func getTokenAuthDetails(p *prompt.Prompter, newProfile *entity.Profile) error {
+	token := &entity.Token{}
+	value, err := p.MaskedText("Bearer token (leave blank if you want to provide a command which prints the token)", nil)
+	if err != nil {
+		return err
+	}
+	if len(value) > 0 {
+		token.Value = value
+	} else if token.Command, err = p.Text("Command which prints bearer token", checkInputIsNotEmpty); err != nil {
+		return err
 	}
-	return strings.TrimSpace(response)
+	newProfile.Token = token
+	return nil
+}
+
+// getAPIKeyAuthDetails gets API key from user using command line
+func getAPIKeyAuthDetails(p *prompt.Prompter, newProfile *entity.Profile) (err error) {
+	newProfile.APIKey, err = p.MaskedText("API key", checkInputIsNotEmpty)
+	return err
+}
+
+// getCredentialProcessDetails gets command which prints credentials as json from user using command line
+func getCredentialProcessDetails(p *prompt.Prompter, newProfile *entity.Profile) (err error) {
+	newProfile.CredentialProcess, err = p.Text("Command which prints credentials as json", checkInputIsNotEmpty)
+	return err
 }
 
 // checkInputIsNotEmpty checks whether input is empty or not
@@ -301,20 +397,15 @@ func checkInputIsNotEmpty(input string) bool {
 	return true
 }
 
-// getUserInputAsMaskedText get value from user as masked text, since credentials like password
-// should not be displayed on console for security reasons
-func getUserInputAsMaskedText(isValid func(string) bool) string {
-	maskedValue, err := term.ReadPassword(0)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-	value := fmt.Sprintf("%s", maskedValue)
-	if !isValid(value) {
-		return getUserInputAsMaskedText(isValid)
+// checkAWSServiceName returns validator which checks whether AWS service name is provided
+func checkAWSServiceName(p *prompt.Prompter) func(string) bool {
+	return func(input string) bool {
+		if err := signer.ValidateService(input); err != nil {
+			p.Printf("%s, please enter valid value: ", err)
+			return false
+		}
+		return true
 	}
-	fmt.Println()
-	return value
 }
 
 //listProfiles list profiles from the config file
@@ -333,12 +424,19 @@ func listProfiles(cmd *cobra.Command) error {
 	return displayCompleteProfiles(profileController)
 }
 
-//displayCompleteProfiles lists complete profile information as below
+//profileSummary represents profile information displayed by list command
+type profileSummary struct {
+	Name     string `json:"name"`
+	UserName string `json:"user"`
+	Endpoint string `json:"endpoint"`
+}
+
+//displayCompleteProfiles lists complete profile information, default format is table as below
 /*
-Name       UserName     Endpoint-url
-----       --------     ------------
-default    admin      	https://localhost:9200
-dev        test      	https://127.0.0.1:9200
+NAME       USER     ENDPOINT
+----       ----     --------
+default    admin    https://localhost:9200
+dev        test     https://127.0.0.1:9200
 */
 func displayCompleteProfiles(p profile.Controller) (err error) {
 	var profiles []entity.Profile
@@ -348,16 +446,15 @@ func displayCompleteProfiles(p profile.Controller) (err error) {
 	if len(profiles) < 1 {
 		return fmt.Errorf("no profiles found")
 	}
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', alignLeft)
-	defer func() {
-		err = w.Flush()
-	}()
-	_, err = fmt.Fprintln(w, "Name\t\tUserName\t\tEndpoint-url\t")
-	_, err = fmt.Fprintf(w, "%s\t\t%s\t\t%s\t\n", "----", "--------", "------------")
+	summaries := make([]profileSummary, 0, len(profiles))
 	for _, p := range profiles {
-		_, err = fmt.Fprintf(w, "%s\t\t%s\t\t%s\t\n", p.Name, p.UserName, p.Endpoint)
+		summaries = append(summaries, profileSummary{
+			Name:     p.Name,
+			UserName: p.UserName,
+			Endpoint: p.Endpoint,
+		})
 	}
-	return
+	return renderOutput(os.Stdout, summaries, OutputTable)
 }
 
 //displayProfileNames lists only profile names
@@ -370,6 +467,9 @@ func displayProfileNames(p profile.Controller) (err error) {
 	if len(names) < 1 {
 		return fmt.Errorf("no profiles found")
 	}
+	if isOutputFormatSet() {
+		return renderOutput(os.Stdout, names, OutputJSON)
+	}
 	for _, name := range names {
 		fmt.Println(name)
 	}
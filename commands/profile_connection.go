@@ -0,0 +1,95 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	ctrl "opensearch-cli/controller/platform"
+	"opensearch-cli/entity"
+	gateway "opensearch-cli/gateway/platform"
+	handler "opensearch-cli/handler/platform"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const TestProfileCommandName = "test"
+
+//testProfileCmd checks whether cluster can be reached and user can be authenticated using profile
+var testProfileCmd = &cobra.Command{
+	Use:   TestProfileCommandName + " [profile_name]",
+	Short: "Test connection to the cluster using profile",
+	Long: "Test connection to the cluster using settings and credentials from the named profile. " +
+		"If profile name is not provided, the profile used for execution is tested.\n" +
+		"Reports whether the cluster is reachable, outcome of TLS verification, authenticated user, " +
+		"cluster name and version, and whether Anomaly Detection and k-NN plugins are installed.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := testProfile(args); err != nil {
+			DisplayError(err, TestProfileCommandName)
+		}
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(testProfileCmd)
+	testProfileCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+TestProfileCommandName)
+}
+
+//testProfile checks connection using profile and displays report, default format is yaml
+func testProfile(args []string) error {
+	p, err := getProfileToTest(args)
+	if err != nil {
+		return err
+	}
+	commandHandler, err := getPlatformHandler(p)
+	if err != nil {
+		return err
+	}
+	report, err := handler.CheckConnection(commandHandler, p.Endpoint)
+	if p.Certificate != nil && p.Certificate.Insecure != nil && *p.Certificate.Insecure && report.TLS == ctrl.TLSVerified {
+		report.TLS = ctrl.TLSSkipped
+	}
+	if renderErr := renderOutput(os.Stdout, report, OutputYAML); renderErr != nil {
+		return renderErr
+	}
+	return err
+}
+
+//getProfileToTest returns profile by name if provided, else, profile used for execution
+func getProfileToTest(args []string) (*entity.Profile, error) {
+	if len(args) < 1 {
+		return GetProfile()
+	}
+	profileController, err := GetProfileController()
+	if err != nil {
+		return nil, err
+	}
+	p, _, err := profileController.GetProfileForExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = applyOverrides(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//getPlatformHandler returns handler for given profile by wiring the dependency manually
+func getPlatformHandler(p *entity.Profile) (*handler.Handler, error) {
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	g, err := gateway.New(c, p)
+	if err != nil {
+		return nil, err
+	}
+	facade := ctrl.New(g)
+	return handler.New(facade), nil
+}
@@ -0,0 +1,62 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"opensearch-cli/environment"
+
+	"github.com/spf13/cobra"
+)
+
+const EncryptSecretsCommandName = "encrypt-secrets"
+
+//encryptSecretsCmd encrypts secrets of existing profiles which are saved as plain text
+var encryptSecretsCmd = &cobra.Command{
+	Use:   EncryptSecretsCommandName,
+	Short: "Encrypt secrets of existing profiles",
+	Long: "Encrypt passwords, tokens and API keys of existing profiles which are saved as plain text in the config file.\n" +
+		"Secrets are encrypted with a key derived from the contents of the file in the " + environment.OPENSEARCH_CLI_KEY_FILE +
+		" environment variable, or the passphrase in the " + environment.OPENSEARCH_CLI_PASSPHRASE + " environment variable.\n" +
+		"Once either is set, new and updated profiles are encrypted automatically.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := encryptSecrets(); err != nil {
+			DisplayError(err, EncryptSecretsCommandName)
+		}
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(encryptSecretsCmd)
+	encryptSecretsCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+EncryptSecretsCommandName)
+}
+
+//encryptSecrets encrypts secrets of every profile in config file
+func encryptSecrets() error {
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	proceed, err := GetPrompter().Confirm("opensearch-cli will encrypt plain text secrets in the config file, " +
+		"they cannot be read without the same key file or passphrase. Do you want to proceed? Y/N ")
+	if err != nil || !proceed {
+		return err
+	}
+	updated, err := profileController.EncryptSecrets()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		fmt.Println("No plain text secrets found.")
+		return nil
+	}
+	fmt.Printf("Encrypted secrets of %d profile(s) successfully.\n", updated)
+	return nil
+}
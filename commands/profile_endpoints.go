@@ -0,0 +1,68 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"opensearch-cli/client"
+	"opensearch-cli/entity"
+	"opensearch-cli/gateway"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagProfileEndpoints         = "endpoints"
+	FlagProfileEndpointSelection = "endpoint-selection"
+	FlagProfileSniff             = "sniff"
+	FlagProfileSniffTTL          = "sniff-ttl"
+)
+
+//addEndpointFlags adds flags to configure endpoints of other nodes of the cluster
+func addEndpointFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(FlagProfileEndpoints, nil, "Comma separated endpoints of other nodes of the cluster, "+
+		"used when endpoint cannot be connected to, or in turn if endpoint selection is round-robin")
+	cmd.Flags().String(FlagProfileEndpointSelection, string(client.Failover), fmt.Sprintf(
+		"How requests are distributed between endpoints. Options are %s and %s", client.Failover, client.RoundRobin))
+	cmd.Flags().Bool(FlagProfileSniff, false, "Discover data and coordinating nodes of the cluster, and send requests "+
+		"to them in turn, unless endpoint selection is failover")
+	cmd.Flags().Int64(FlagProfileSniffTTL, 300, "Seconds discovered nodes are cached before nodes are discovered again")
+}
+
+//applyEndpointFlags updates endpoints of profile only for flags provided by user
+func applyEndpointFlags(cmd *cobra.Command, p *entity.Profile) error {
+	flags := cmd.Flags()
+	if flags.Changed(FlagProfileEndpoints) {
+		p.Endpoints, _ = flags.GetStringSlice(FlagProfileEndpoints)
+	}
+	if flags.Changed(FlagProfileEndpointSelection) {
+		selection, _ := flags.GetString(FlagProfileEndpointSelection)
+		if selection != string(client.Failover) && selection != string(client.RoundRobin) {
+			return fmt.Errorf("invalid value for %s. Options are %s and %s", FlagProfileEndpointSelection, client.Failover, client.RoundRobin)
+		}
+		p.EndpointSelection = selection
+	}
+	if flags.Changed(FlagProfileSniff) || flags.Changed(FlagProfileSniffTTL) {
+		if p.Sniff == nil {
+			p.Sniff = &entity.Sniff{}
+		}
+		if flags.Changed(FlagProfileSniff) {
+			enabled, _ := flags.GetBool(FlagProfileSniff)
+			p.Sniff.Enabled = &enabled
+		}
+		if flags.Changed(FlagProfileSniffTTL) {
+			ttl, _ := flags.GetInt64(FlagProfileSniffTTL)
+			p.Sniff.TTL = &ttl
+		}
+		if _, err := gateway.GetSniffTTL(p.Sniff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
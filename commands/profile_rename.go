@@ -0,0 +1,46 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const RenameProfileCommandName = "rename"
+
+//renameProfileCmd renames an existing profile
+var renameProfileCmd = &cobra.Command{
+	Use:   RenameProfileCommandName + " profile_name new_profile_name",
+	Short: "Rename profile",
+	Long:  "Rename an existing named profile. New name should not be used by any other profile.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := renameProfile(args[0], args[1]); err != nil {
+			DisplayError(err, RenameProfileCommandName)
+			return
+		}
+		fmt.Println("Profile renamed successfully.")
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(renameProfileCmd)
+	renameProfileCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+RenameProfileCommandName)
+}
+
+//renameProfile renames profile from name to newName
+func renameProfile(name string, newName string) error {
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	return profileController.RenameProfile(name, newName)
+}
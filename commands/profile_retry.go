@@ -0,0 +1,83 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"opensearch-cli/entity"
+	"opensearch-cli/gateway"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagProfileRetryMinBackoff    = "retry-min-backoff"
+	FlagProfileRetryMaxBackoff    = "retry-max-backoff"
+	FlagProfileRetryJitter        = "retry-jitter"
+	FlagProfileRetryStatusCodes   = "retry-status-codes"
+	FlagProfileHonorRetryAfter    = "honor-retry-after"
+	FlagProfileRetryNonIdempotent = "retry-non-idempotent"
+)
+
+//addRetryFlags adds flags to configure retry policy of profile
+func addRetryFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64(FlagProfileRetryMinBackoff, 1000, "Wait before first retry in milliseconds, wait is doubled for every retry")
+	cmd.Flags().Int64(FlagProfileRetryMaxBackoff, 30000, "Maximum wait between retries in milliseconds")
+	cmd.Flags().Bool(FlagProfileRetryJitter, false, "Wait for random duration up to backoff, so that clients don't retry together")
+	cmd.Flags().IntSlice(FlagProfileRetryStatusCodes, []int{429, 502, 503, 504}, "Status codes which are retried")
+	cmd.Flags().Bool(FlagProfileHonorRetryAfter, true, "Wait as long as Retry-After header of response asks, up to maximum backoff")
+	cmd.Flags().Bool(FlagProfileRetryNonIdempotent, false, "Retry POST and PATCH requests, other than searches, which may have modified data before failing")
+}
+
+//applyRetryFlags updates retry policy of profile only for flags provided by user, so that settings which are
+//not provided are inherited
+func applyRetryFlags(cmd *cobra.Command, p *entity.Profile) {
+	flags := cmd.Flags()
+	retry := entity.Retry{}
+	if p.Retry != nil {
+		retry = *p.Retry
+	}
+	if flags.Changed(FlagProfileRetryMinBackoff) {
+		value, _ := flags.GetInt64(FlagProfileRetryMinBackoff)
+		retry.MinBackoff = &value
+	}
+	if flags.Changed(FlagProfileRetryMaxBackoff) {
+		value, _ := flags.GetInt64(FlagProfileRetryMaxBackoff)
+		retry.MaxBackoff = &value
+	}
+	if flags.Changed(FlagProfileRetryJitter) {
+		value, _ := flags.GetBool(FlagProfileRetryJitter)
+		retry.Jitter = &value
+	}
+	if flags.Changed(FlagProfileRetryStatusCodes) {
+		retry.StatusCodes, _ = flags.GetIntSlice(FlagProfileRetryStatusCodes)
+	}
+	if flags.Changed(FlagProfileHonorRetryAfter) {
+		value, _ := flags.GetBool(FlagProfileHonorRetryAfter)
+		retry.HonorRetryAfter = &value
+	}
+	if flags.Changed(FlagProfileRetryNonIdempotent) {
+		value, _ := flags.GetBool(FlagProfileRetryNonIdempotent)
+		retry.RetryNonIdempotent = &value
+	}
+	if retry.MinBackoff == nil && retry.MaxBackoff == nil && retry.Jitter == nil && len(retry.StatusCodes) == 0 &&
+		retry.HonorRetryAfter == nil && retry.RetryNonIdempotent == nil {
+		p.Retry = nil
+		return
+	}
+	p.Retry = &retry
+}
+
+//validateRetry checks retry policy before profile is saved, so that invalid settings don't fail every command
+func validateRetry(retry *entity.Retry) error {
+	if retry == nil {
+		return nil
+	}
+	_, err := gateway.GetRetryPolicy(retry)
+	return err
+}
@@ -0,0 +1,147 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"opensearch-cli/entity"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ShowProfileCommandName = "show"
+	FlagProfileResolved    = "resolved"
+	maskedSecret           = "********"
+)
+
+//showProfileCmd displays all settings of a profile with secrets masked
+var showProfileCmd = &cobra.Command{
+	Use:   ShowProfileCommandName + " profile_name",
+	Short: "Show profile",
+	Long: "Show all settings of a named profile. Secrets like password are masked.\n" +
+		"Use --resolved to show settings inherited from extended profiles and config defaults, along with where every setting came from.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resolved, _ := cmd.Flags().GetBool(FlagProfileResolved)
+		if resolved {
+			if err := showResolvedProfile(os.Stdout, args[0]); err != nil {
+				DisplayError(err, ShowProfileCommandName)
+			}
+			return
+		}
+		if err := showProfile(args[0]); err != nil {
+			DisplayError(err, ShowProfileCommandName)
+		}
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(showProfileCmd)
+	showProfileCmd.Flags().Bool(FlagProfileResolved, false, "Show profile merged with extended profiles and config defaults")
+	showProfileCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+ShowProfileCommandName)
+}
+
+//showProfile displays profile by name, default format is yaml, same as config file
+func showProfile(name string) error {
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	profiles, err := profileController.GetProfilesMap()
+	if err != nil {
+		return err
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+	return renderOutput(os.Stdout, maskProfileSecrets(p), OutputYAML)
+}
+
+//showResolvedProfile displays profile merged with extended profiles and config defaults. In yaml, source of
+//every setting is added as comment, in other formats, sources are displayed along with profile
+func showResolvedProfile(w io.Writer, name string) error {
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	p, err := profileController.ResolveProfile(name)
+	if err != nil {
+		return err
+	}
+	p.Profile = maskProfileSecrets(p.Profile)
+	format, err := getOutputFormat(OutputYAML)
+	if err != nil {
+		return err
+	}
+	if format != OutputYAML {
+		return Render(w, format, p)
+	}
+	contents, err := toJSON(p.Profile)
+	if err != nil {
+		return err
+	}
+	document, err := decodeOrdered(contents)
+	if err != nil {
+		return err
+	}
+	node := toYAMLNode(document)
+	annotateSources(node, "", p.Sources)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+//annotateSources adds source of every setting in mapping as line comment
+func annotateSources(mapping *yaml.Node, prefix string, sources map[string]string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := prefix+mapping.Content[i].Value, mapping.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			annotateSources(value, key+".", sources)
+			continue
+		}
+		source, ok := sources[key]
+		switch {
+		case !ok:
+			continue
+		case source == "":
+			value.LineComment = "from defaults"
+		default:
+			value.LineComment = fmt.Sprintf("from profile '%s'", source)
+		}
+	}
+}
+
+//maskProfileSecrets returns copy of profile where secrets are replaced by mask
+func maskProfileSecrets(p entity.Profile) entity.Profile {
+	if len(p.Password) > 0 {
+		p.Password = maskedSecret
+	}
+	if len(p.APIKey) > 0 {
+		p.APIKey = maskedSecret
+	}
+	if p.Token != nil && len(p.Token.Value) > 0 {
+		token := *p.Token
+		token.Value = maskedSecret
+		p.Token = &token
+	}
+	if p.Proxy != nil && len(p.Proxy.Password) > 0 {
+		proxy := *p.Proxy
+		proxy.Password = maskedSecret
+		p.Proxy = &proxy
+	}
+	return p
+}
@@ -29,12 +29,19 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"opensearch-cli/controller/config"
+	"opensearch-cli/controller/profile"
 	"opensearch-cli/controller/profile/mocks"
 	"opensearch-cli/entity"
+	"opensearch-cli/mapper"
+	"opensearch-cli/prompt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -155,14 +162,14 @@ func TestDeleteProfileCommand(t *testing.T) {
 			err := os.Remove(f.Name())
 			assert.NoError(t, err)
 		}()
-		config := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
-		bytes, err := yaml.Marshal(config)
+		cfg := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
+		bytes, err := yaml.Marshal(cfg)
 		assert.NoError(t, err)
 		assert.NoError(t, ioutil.WriteFile(f.Name(), bytes, 0644))
 		assert.NoError(t, f.Sync())
 		root := GetRoot()
 		assert.NotNil(t, root)
-		root.SetArgs([]string{ProfileCommandName, DeleteProfilesCommandName, config.Profiles[0].Name, "--config", f.Name()})
+		root.SetArgs([]string{ProfileCommandName, DeleteProfilesCommandName, cfg.Profiles[0].Name, "--config", f.Name()})
 		cmd, err := root.ExecuteC()
 		assert.NoError(t, err)
 		expected, err := cmd.Flags().GetString(flagConfig)
@@ -173,7 +180,7 @@ func TestDeleteProfileCommand(t *testing.T) {
 		assert.NoError(t, err)
 		err = yaml.Unmarshal(contents, &expectedConfig)
 		assert.NoError(t, err)
-		assert.EqualValues(t, expectedConfig, entity.Config{Profiles: []entity.Profile{}})
+		assert.EqualValues(t, expectedConfig, entity.Config{Version: config.CurrentVersion, Profiles: []entity.Profile{}})
 	})
 }
 
@@ -185,8 +192,8 @@ func TestListsProfileCommand(t *testing.T) {
 			err := os.Remove(f.Name())
 			assert.NoError(t, err)
 		}()
-		config := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
-		bytes, err := yaml.Marshal(config)
+		cfg := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
+		bytes, err := yaml.Marshal(cfg)
 		assert.NoError(t, err)
 		assert.NoError(t, ioutil.WriteFile(f.Name(), bytes, 0644))
 		assert.NoError(t, f.Sync())
@@ -206,8 +213,8 @@ func TestListsProfileCommand(t *testing.T) {
 			err := os.Remove(f.Name())
 			assert.NoError(t, err)
 		}()
-		config := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
-		bytes, err := yaml.Marshal(config)
+		cfg := entity.Config{Profiles: []entity.Profile{fakeInputProfile()}}
+		bytes, err := yaml.Marshal(cfg)
 		assert.NoError(t, err)
 		assert.NoError(t, ioutil.WriteFile(f.Name(), bytes, 0644))
 		assert.NoError(t, f.Sync())
@@ -242,3 +249,378 @@ func TestListsProfileCommand(t *testing.T) {
 		assert.EqualValues(t, expected, f.Name())
 	})
 }
+
+func TestGetAuthDetails(t *testing.T) {
+	t.Run("basic auth details from user input", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("admin\nadmin\n"), false, false), "basic", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, fakeInputProfile(), newProfile)
+	})
+	t.Run("prompts are disabled", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("admin\nadmin\n"), true, true), "basic", &newProfile)
+		assert.EqualError(t, err, "user input is required for 'Username', but prompts are disabled")
+	})
+	t.Run("security disabled doesn't require input", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader(""), false, true), "disabled", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, fakeInSecuredInputProfile(), newProfile)
+	})
+	t.Run("static bearer token", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("eyJhbGciOi\n"), false, false), "token", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.Token{Value: "eyJhbGciOi"}, newProfile.Token)
+	})
+	t.Run("bearer token command", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("\noidc-token --audience opensearch\n"), false, false), "token", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.Token{Command: "oidc-token --audience opensearch"}, newProfile.Token)
+	})
+	t.Run("api key", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw==\n"), false, false), "api-key", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw==", newProfile.APIKey)
+	})
+	t.Run("credential process", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("vault read -format=json secret/opensearch\n"), false, false), "credential-process", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "vault read -format=json secret/opensearch", newProfile.CredentialProcess)
+	})
+	t.Run("aws iam assume role", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		input := "\nes\nus-west-2\narn:aws:iam::123456789012:role/search\nexternal-id\n/var/run/token\n"
+		err := getAuthDetails(prompt.New(strings.NewReader(input), false, false), "aws-iam", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.AWSIAM{
+			ServiceName:          "es",
+			Region:               "us-west-2",
+			RoleARN:              "arn:aws:iam::123456789012:role/search",
+			ExternalID:           "external-id",
+			WebIdentityTokenFile: "/var/run/token",
+		}, newProfile.AWS)
+	})
+	t.Run("aws iam without role", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("readonly\nes\n\n\n"), false, false), "aws-iam", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.AWSIAM{ProfileName: "readonly", ServiceName: "es"}, newProfile.AWS)
+	})
+	t.Run("aws iam serverless", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("\n\naoss\nus-east-1\n\n"), false, false), "aws-iam", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.AWSIAM{ServiceName: "aoss", Region: "us-east-1"}, newProfile.AWS)
+	})
+	t.Run("aws iam other service", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader("\nec2\n\n\n"), false, false), "aws-iam", &newProfile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, &entity.AWSIAM{ServiceName: "ec2"}, newProfile.AWS)
+	})
+	t.Run("invalid auth type", func(t *testing.T) {
+		newProfile := fakeInSecuredInputProfile()
+		err := getAuthDetails(prompt.New(strings.NewReader(""), false, false), "kerberos", &newProfile)
+		assert.EqualError(t, err, "invalid value for auth-type. Use --help -h command to see permitted values")
+	})
+}
+
+func writeFakeConfig(t *testing.T, profiles ...entity.Profile) string {
+	f, err := ioutil.TempFile("", "profile")
+	assert.NoError(t, err)
+	bytes, err := yaml.Marshal(entity.Config{Profiles: profiles})
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(f.Name(), bytes, 0600))
+	assert.NoError(t, f.Close())
+	return f.Name()
+}
+
+func readFakeConfig(t *testing.T, path string) entity.Config {
+	var config entity.Config
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.Unmarshal(contents, &config))
+	return config
+}
+
+func TestUpdateProfileCommand(t *testing.T) {
+	t.Run("update endpoint and certificate", func(t *testing.T) {
+		configFile := writeFakeConfig(t, fakeInputProfile())
+		defer func() {
+			assert.NoError(t, os.Remove(configFile))
+		}()
+		root := GetRoot()
+		root.SetArgs([]string{ProfileCommandName, UpdateProfileCommandName, "default",
+			"--" + FlagProfileCreateEndpoint, "https://127.0.0.1:9200",
+			"--" + FlagProfileTimeout, "30",
+			"--" + FlagProfileCACert, "/tmp/ca.pem",
+			"--" + flagConfig, configFile})
+		_, err := root.ExecuteC()
+		assert.NoError(t, err)
+		expected := fakeInputProfile()
+		expected.Endpoint = "https://127.0.0.1:9200"
+		timeout := int64(30)
+		expected.Timeout = &timeout
+		ca := "/tmp/ca.pem"
+		expected.Certificate = &entity.Trust{CAFilePath: &ca}
+		assert.EqualValues(t, entity.Config{Version: config.CurrentVersion, Profiles: []entity.Profile{expected}}, readFakeConfig(t, configFile))
+	})
+	t.Run("update retry policy", func(t *testing.T) {
+		configFile := writeFakeConfig(t, fakeInputProfile())
+		resetFlags(t, updateProfileCmd)
+		defer func() {
+			assert.NoError(t, os.Remove(configFile))
+			resetFlags(t, updateProfileCmd)
+		}()
+		root := GetRoot()
+		root.SetArgs([]string{ProfileCommandName, UpdateProfileCommandName, "default",
+			"--" + FlagProfileRetryMaxBackoff, "5000",
+			"--" + FlagProfileRetryStatusCodes, "429,503",
+			"--" + FlagProfileHonorRetryAfter + "=false",
+			"--" + flagConfig, configFile})
+		_, err := root.ExecuteC()
+		assert.NoError(t, err)
+		maxBackoff := int64(5000)
+		honorRetryAfter := false
+		expected := fakeInputProfile()
+		expected.Retry = &entity.Retry{
+			MaxBackoff:      &maxBackoff,
+			StatusCodes:     []int{429, 503},
+			HonorRetryAfter: &honorRetryAfter,
+		}
+		assert.EqualValues(t, []entity.Profile{expected}, readFakeConfig(t, configFile).Profiles)
+	})
+}
+
+//resetFlags resets flags changed by previous tests, since commands are shared by tests
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			assert.NoError(t, slice.Replace(strings.Split(strings.Trim(flag.DefValue, "[]"), ",")))
+		} else {
+			assert.NoError(t, flag.Value.Set(flag.DefValue))
+		}
+		flag.Changed = false
+	})
+}
+
+func TestApplyEndpointFlags(t *testing.T) {
+	defer resetFlags(t, updateProfileCmd)
+	p := fakeInputProfile()
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileEndpoints, "https://node-2:9200,https://node-3:9200"))
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileEndpointSelection, "round-robin"))
+	assert.NoError(t, applyEndpointFlags(updateProfileCmd, &p))
+	assert.EqualValues(t, []string{"https://node-2:9200", "https://node-3:9200"}, p.Endpoints)
+	assert.EqualValues(t, "round-robin", p.EndpointSelection)
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileEndpointSelection, "random"))
+	assert.EqualError(t, applyEndpointFlags(updateProfileCmd, &p), "invalid value for endpoint-selection. Options are failover and round-robin")
+}
+
+func TestApplySniffFlags(t *testing.T) {
+	defer resetFlags(t, updateProfileCmd)
+	p := fakeInputProfile()
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileSniff, "true"))
+	assert.NoError(t, applyEndpointFlags(updateProfileCmd, &p))
+	assert.EqualValues(t, mapper.BoolToBoolPtr(true), p.Sniff.Enabled)
+	assert.Nil(t, p.Sniff.TTL)
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileSniffTTL, "-1"))
+	assert.EqualError(t, applyEndpointFlags(updateProfileCmd, &p), "invalid sniff ttl -1, ttl should not be negative")
+}
+
+func TestValidateRetry(t *testing.T) {
+	minBackoff, maxBackoff := int64(2000), int64(1000)
+	assert.NoError(t, validateRetry(nil))
+	assert.NoError(t, validateRetry(&entity.Retry{MinBackoff: &maxBackoff}))
+	assert.EqualError(t, validateRetry(&entity.Retry{MinBackoff: &minBackoff, MaxBackoff: &maxBackoff}),
+		"invalid retry backoff, min_backoff 2s should not be negative or greater than max_backoff 1s")
+	assert.EqualError(t, validateRetry(&entity.Retry{StatusCodes: []int{429, 1000}}), "invalid retry status code 1000")
+}
+
+func TestApplyProfileFlagsAuthType(t *testing.T) {
+	defer resetFlags(t, updateProfileCmd)
+	ca := "/tmp/ca.pem"
+	p := fakeInputProfile()
+	p.Certificate = &entity.Trust{
+		CAFilePath:                &ca,
+		ClientCertificateFilePath: mapper.StringToStringPtr("/tmp/client.pem"),
+		ClientKeyFilePath:         mapper.StringToStringPtr("/tmp/client.key"),
+		Insecure:                  mapper.BoolToBoolPtr(false),
+		ServerName:                "node-1",
+		MinTLSVersion:             "1.2",
+	}
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileCreateAuthType, "disabled"))
+	assert.NoError(t, applyProfileFlags(updateProfileCmd, &p))
+	assert.EqualValues(t, &entity.Trust{
+		CAFilePath:    &ca,
+		Insecure:      mapper.BoolToBoolPtr(false),
+		ServerName:    "node-1",
+		MinTLSVersion: "1.2",
+	}, p.Certificate, "only client certificate is removed when authentication type is changed")
+	assert.Empty(t, p.UserName)
+	assert.Empty(t, p.Password)
+}
+
+func TestApplyTLSFlags(t *testing.T) {
+	defer func() {
+		for _, name := range []string{FlagProfileInsecure, FlagProfileServerName, FlagProfileMinTLSVersion} {
+			flag := updateProfileCmd.Flags().Lookup(name)
+			assert.NoError(t, flag.Value.Set(flag.DefValue))
+			flag.Changed = false
+		}
+	}()
+	p := fakeInputProfile()
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileInsecure, "true"))
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileServerName, "node-1"))
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileMinTLSVersion, "1.2"))
+	assert.NoError(t, applyTLSFlags(updateProfileCmd, &p))
+	assert.EqualValues(t, &entity.Trust{Insecure: mapper.BoolToBoolPtr(true), ServerName: "node-1", MinTLSVersion: "1.2"}, p.Certificate)
+
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileInsecure, "false"))
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileServerName, ""))
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileMinTLSVersion, ""))
+	assert.NoError(t, applyTLSFlags(updateProfileCmd, &p))
+	assert.EqualValues(t, &entity.Trust{Insecure: mapper.BoolToBoolPtr(false)}, p.Certificate,
+		"insecure=false is kept, so that it overrides profile which is extended")
+
+	resetFlags(t, updateProfileCmd)
+	p.Certificate = &entity.Trust{ServerName: "node-1"}
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileServerName, ""))
+	assert.NoError(t, applyTLSFlags(updateProfileCmd, &p))
+	assert.Nil(t, p.Certificate)
+
+	assert.NoError(t, updateProfileCmd.Flags().Set(FlagProfileMinTLSVersion, "1.4"))
+	assert.EqualError(t, applyTLSFlags(updateProfileCmd, &p), "invalid min_tls_version 1.4. Options are 1.0, 1.1, 1.2, 1.3")
+}
+
+func TestRenameProfileCommand(t *testing.T) {
+	t.Run("rename profile", func(t *testing.T) {
+		configFile := writeFakeConfig(t, fakeInputProfile())
+		defer func() {
+			assert.NoError(t, os.Remove(configFile))
+		}()
+		root := GetRoot()
+		root.SetArgs([]string{ProfileCommandName, RenameProfileCommandName, "default", "dev", "--" + flagConfig, configFile})
+		_, err := root.ExecuteC()
+		assert.NoError(t, err)
+		expected := fakeInputProfile()
+		expected.Name = "dev"
+		assert.EqualValues(t, entity.Config{Version: config.CurrentVersion, Profiles: []entity.Profile{expected}}, readFakeConfig(t, configFile))
+	})
+}
+
+func TestMaskProfileSecrets(t *testing.T) {
+	t.Run("password is masked", func(t *testing.T) {
+		masked := maskProfileSecrets(fakeInputProfile())
+		assert.EqualValues(t, maskedSecret, masked.Password)
+		assert.EqualValues(t, "admin", masked.UserName)
+	})
+	t.Run("proxy password is masked", func(t *testing.T) {
+		p := fakeInSecuredInputProfile()
+		p.Proxy = &entity.Proxy{URL: "http://proxy:3128", UserName: "user", Password: "secret"}
+		masked := maskProfileSecrets(p)
+		assert.EqualValues(t, maskedSecret, masked.Proxy.Password)
+		assert.EqualValues(t, "secret", p.Proxy.Password)
+	})
+	t.Run("token and api key are masked", func(t *testing.T) {
+		p := fakeInSecuredInputProfile()
+		p.Token = &entity.Token{Value: "eyJhbGciOi"}
+		p.APIKey = "secret-key"
+		masked := maskProfileSecrets(p)
+		assert.EqualValues(t, maskedSecret, masked.Token.Value)
+		assert.EqualValues(t, maskedSecret, masked.APIKey)
+		assert.EqualValues(t, "eyJhbGciOi", p.Token.Value)
+	})
+	t.Run("no password", func(t *testing.T) {
+		masked := maskProfileSecrets(fakeInSecuredInputProfile())
+		assert.Empty(t, masked.Password)
+	})
+}
+
+func TestShowResolvedProfile(t *testing.T) {
+	t.Run("settings are annotated with source", func(t *testing.T) {
+		maxRetry := 3
+		configFile := writeFakeConfig(t,
+			entity.Profile{Name: "base", UserName: "admin", Password: "admin"},
+			entity.Profile{Name: "prod", Extends: "base", Endpoint: "https://prod:9200"},
+		)
+		defer func() {
+			assert.NoError(t, os.Remove(configFile))
+		}()
+		cfg := readFakeConfig(t, configFile)
+		cfg.Defaults = &entity.Profile{MaxRetry: &maxRetry}
+		contents, err := yaml.Marshal(cfg)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(configFile, contents, FilePermission))
+		root := GetRoot()
+		root.SetArgs([]string{"--config", configFile})
+		_, err = root.ExecuteC()
+		assert.NoError(t, err)
+		var output strings.Builder
+		assert.NoError(t, showResolvedProfile(&output, "prod"))
+		assert.EqualValues(t, `name: prod
+extends: base
+endpoint: https://prod:9200 # from profile 'prod'
+user: admin # from profile 'base'
+password: '********' # from profile 'base'
+max_retry: 3 # from defaults
+`, output.String())
+	})
+}
+
+func TestExportImportProfiles(t *testing.T) {
+	source := fakeInputProfile()
+	source.Name = "prod"
+	exportFile := writeFakeConfig(t, source)
+	importFile := writeFakeConfig(t, fakeInputProfile())
+	defer func() {
+		assert.NoError(t, os.Remove(exportFile))
+		assert.NoError(t, os.Remove(importFile))
+	}()
+	root := GetRoot()
+	var exported strings.Builder
+	t.Run("export strips secrets", func(t *testing.T) {
+		root.SetArgs([]string{"--config", exportFile})
+		_, err := root.ExecuteC()
+		assert.NoError(t, err)
+		assert.NoError(t, exportProfiles(&exported, []string{"prod"}, profile.SecretsStrip))
+		assert.EqualValues(t, "version: 1\nprofiles:\n  - name: prod\n    endpoint: localhost:9200\n    user: admin\n", exported.String())
+	})
+	t.Run("import", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "exported")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.Remove(f.Name()))
+		}()
+		_, err = f.WriteString(exported.String())
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		root.SetArgs([]string{"--config", importFile})
+		_, err = root.ExecuteC()
+		assert.NoError(t, err)
+		var output strings.Builder
+		assert.NoError(t, importProfiles(&output, f.Name(), profile.ConflictFail))
+		assert.EqualValues(t, "Imported: prod\n", output.String())
+		assert.EqualValues(t, []entity.Profile{fakeInputProfile(), {Name: "prod", Endpoint: "localhost:9200", UserName: "admin"}},
+			readFakeConfig(t, importFile).Profiles)
+		assert.EqualError(t, importProfiles(&output, f.Name(), profile.ConflictFail), "profiles already exist: prod")
+
+		setPromptFlag(t, flagNoInput)
+		assert.EqualError(t, importProfiles(&output, f.Name(), profile.ConflictOverwrite), "user input is required for "+
+			"'opensearch-cli will overwrite profile(s): prod . Do you want to proceed? Y/N', but prompts are disabled")
+	})
+	t.Run("only one conflict policy", func(t *testing.T) {
+		assert.NoError(t, importProfilesCmd.Flags().Set(FlagProfileSkip, "true"))
+		assert.NoError(t, importProfilesCmd.Flags().Set(FlagProfileRename, "true"))
+		defer func() {
+			assert.NoError(t, importProfilesCmd.Flags().Set(FlagProfileSkip, "false"))
+			assert.NoError(t, importProfilesCmd.Flags().Set(FlagProfileRename, "false"))
+		}()
+		_, err := getConflictPolicy(importProfilesCmd)
+		assert.EqualError(t, err, "only one of --rename, --skip can be provided")
+	})
+}
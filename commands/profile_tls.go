@@ -0,0 +1,60 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"opensearch-cli/entity"
+	"opensearch-cli/gateway"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagProfileInsecure      = "insecure"
+	FlagProfileServerName    = "server-name"
+	FlagProfileMinTLSVersion = "min-tls-version"
+)
+
+//addTLSFlags adds flags to configure how certificate of cluster is verified
+func addTLSFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagProfileInsecure, false, "Skip verification of cluster's certificate, use only for testing")
+	cmd.Flags().String(FlagProfileServerName, "", "Server name used to verify cluster's certificate, if it is different from the host of endpoint")
+	cmd.Flags().String(FlagProfileMinTLSVersion, "", "Minimum TLS version. Options are 1.0, 1.1, 1.2 and 1.3")
+}
+
+//applyTLSFlags updates certificate settings of profile only for flags provided by user. Certificate settings are
+//removed if none of them is left
+func applyTLSFlags(cmd *cobra.Command, p *entity.Profile) error {
+	flags := cmd.Flags()
+	trust := entity.Trust{}
+	if p.Certificate != nil {
+		trust = *p.Certificate
+	}
+	if flags.Changed(FlagProfileInsecure) {
+		insecure, _ := flags.GetBool(FlagProfileInsecure)
+		trust.Insecure = &insecure
+	}
+	if flags.Changed(FlagProfileServerName) {
+		trust.ServerName, _ = flags.GetString(FlagProfileServerName)
+	}
+	if flags.Changed(FlagProfileMinTLSVersion) {
+		trust.MinTLSVersion, _ = flags.GetString(FlagProfileMinTLSVersion)
+		if len(trust.MinTLSVersion) > 0 {
+			if _, err := gateway.GetTLSVersion(trust.MinTLSVersion); err != nil {
+				return err
+			}
+		}
+	}
+	if trust == (entity.Trust{}) {
+		p.Certificate = nil
+		return nil
+	}
+	p.Certificate = &trust
+	return nil
+}
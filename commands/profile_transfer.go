@@ -0,0 +1,207 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"opensearch-cli/controller/config"
+	"opensearch-cli/controller/profile"
+	"opensearch-cli/entity"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	ExportProfilesCommandName = "export"
+	ImportProfilesCommandName = "import"
+	FlagProfileSecrets        = "secrets"
+	FlagProfileOverwrite      = "overwrite"
+	FlagProfileSkip           = "skip"
+	FlagProfileRename         = "rename"
+	stdinFileName             = "-"
+)
+
+//exportProfilesCmd writes selected profiles without secrets, so that they can be shared and imported
+var exportProfilesCmd = &cobra.Command{
+	Use:   ExportProfilesCommandName + " [profile_name ...]",
+	Short: "Export profiles",
+	Long: "Export named profiles, or every profile if names are not provided, as yaml or json on stdout.\n" +
+		"Secrets like password are removed, or redacted with --secrets redact, so that exported profiles can be shared.\n" +
+		"Password in the proxy URL is always removed. Settings inherited through extends and defaults are included, " +
+		"so that exported profiles don't depend on profiles which are not exported.\n" +
+		"Exported profiles can be imported using `" + RootCommandName + " " + ProfileCommandName + " " + ImportProfilesCommandName + "`.",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, _ := cmd.Flags().GetString(FlagProfileSecrets)
+		if err := exportProfiles(os.Stdout, args, profile.SecretPolicy(policy)); err != nil {
+			DisplayError(err, ExportProfilesCommandName)
+		}
+	},
+}
+
+//importProfilesCmd saves profiles exported by export command into config file
+var importProfilesCmd = &cobra.Command{
+	Use:   ImportProfilesCommandName + " file",
+	Short: "Import profiles",
+	Long: "Import profiles from yaml or json file created by `" + RootCommandName + " " + ProfileCommandName + " " + ExportProfilesCommandName +
+		"`, use - to read from stdin.\nIf a profile with same name already exists, import fails unless --overwrite, --skip or --rename is provided.\n" +
+		"Redacted secrets, and secrets encrypted with another key, are not imported, use `" + RootCommandName + " " + ProfileCommandName + " " + UpdateProfileCommandName + "` to provide them.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := getConflictPolicy(cmd)
+		if err != nil {
+			DisplayError(err, ImportProfilesCommandName)
+			return
+		}
+		if err = importProfiles(os.Stdout, args[0], policy); err != nil {
+			DisplayError(err, ImportProfilesCommandName)
+		}
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(exportProfilesCmd)
+	exportProfilesCmd.Flags().String(FlagProfileSecrets, string(profile.SecretsStrip), fmt.Sprintf(
+		"How secrets are exported, options are %s and %s. If %s, secrets are replaced by %s",
+		profile.SecretsStrip, profile.SecretsRedact, profile.SecretsRedact, profile.RedactedSecret))
+	exportProfilesCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+ExportProfilesCommandName)
+
+	profileCommand.AddCommand(importProfilesCmd)
+	importProfilesCmd.Flags().Bool(FlagProfileOverwrite, false, "Replace existing profiles which have same name as imported profiles")
+	importProfilesCmd.Flags().Bool(FlagProfileSkip, false, "Keep existing profiles which have same name as imported profiles, and ignore imported profiles")
+	importProfilesCmd.Flags().Bool(FlagProfileRename, false, "Save imported profiles whose name is already used with new name")
+	importProfilesCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+ImportProfilesCommandName)
+}
+
+//exportProfiles writes profiles as config file, so that import can read it like any other config file
+func exportProfiles(w io.Writer, names []string, policy profile.SecretPolicy) error {
+	format, err := getOutputFormat(OutputYAML)
+	if err != nil {
+		return err
+	}
+	if format != OutputYAML && format != OutputJSON {
+		return fmt.Errorf("profiles can be exported only as %s or %s", OutputYAML, OutputJSON)
+	}
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	profiles, err := profileController.ExportProfiles(names, policy)
+	if err != nil {
+		return err
+	}
+	return Render(w, format, entity.Config{Version: config.CurrentVersion, Profiles: profiles})
+}
+
+//getConflictPolicy returns conflict policy based on flags, only one of the flags can be provided
+func getConflictPolicy(cmd *cobra.Command) (profile.ConflictPolicy, error) {
+	policies := map[string]profile.ConflictPolicy{
+		FlagProfileOverwrite: profile.ConflictOverwrite,
+		FlagProfileSkip:      profile.ConflictSkip,
+		FlagProfileRename:    profile.ConflictRename,
+	}
+	var selected []string
+	for flag := range policies {
+		if enabled, _ := cmd.Flags().GetBool(flag); enabled {
+			selected = append(selected, flag)
+		}
+	}
+	switch len(selected) {
+	case 0:
+		return profile.ConflictFail, nil
+	case 1:
+		return policies[selected[0]], nil
+	}
+	sort.Strings(selected)
+	return "", fmt.Errorf("only one of --%s can be provided", strings.Join(selected, ", --"))
+}
+
+//importProfiles reads profiles from file and saves them in config file, summary is written on writer
+func importProfiles(w io.Writer, fileName string, policy profile.ConflictPolicy) error {
+	var contents []byte
+	var err error
+	if fileName == stdinFileName {
+		contents, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		contents, err = ioutil.ReadFile(fileName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read profiles due to: %w", err)
+	}
+	imported, err := config.Decode(contents)
+	if err != nil {
+		return fmt.Errorf("failed to read profiles due to: %w", err)
+	}
+	if len(imported.Profiles) == 0 {
+		return fmt.Errorf("no profiles found in %s", fileName)
+	}
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	if policy == profile.ConflictOverwrite {
+		proceed, err := confirmOverwrite(profileController, imported.Profiles)
+		if err != nil || !proceed {
+			return err
+		}
+	}
+	result, err := profileController.ImportProfiles(imported.Profiles, policy)
+	if err != nil {
+		return err
+	}
+	printImportResult(w, result)
+	return nil
+}
+
+//confirmOverwrite asks user to confirm before existing profiles are replaced by imported profiles
+func confirmOverwrite(profileController profile.Controller, profiles []entity.Profile) (bool, error) {
+	names, err := profileController.GetProfileNames()
+	if err != nil {
+		return false, err
+	}
+	existing := map[string]bool{}
+	for _, name := range names {
+		existing[name] = true
+	}
+	var overwritten []string
+	for _, p := range profiles {
+		if existing[p.Name] {
+			overwritten = append(overwritten, p.Name)
+		}
+	}
+	if len(overwritten) == 0 {
+		return true, nil
+	}
+	return GetPrompter().Confirm(fmt.Sprintf(
+		"opensearch-cli will overwrite profile(s): %s . Do you want to proceed? Y/N ", strings.Join(overwritten, ", ")))
+}
+
+func printImportResult(w io.Writer, result profile.ImportResult) {
+	if len(result.Created) > 0 {
+		fmt.Fprintf(w, "Imported: %s\n", strings.Join(result.Created, ", "))
+	}
+	if len(result.Overwritten) > 0 {
+		fmt.Fprintf(w, "Overwritten: %s\n", strings.Join(result.Overwritten, ", "))
+	}
+	var renamed []string
+	for name, newName := range result.Renamed {
+		renamed = append(renamed, fmt.Sprintf("%s as %s", name, newName))
+	}
+	if len(renamed) > 0 {
+		sort.Strings(renamed)
+		fmt.Fprintf(w, "Imported with new name: %s\n", strings.Join(renamed, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped: %s\n", strings.Join(result.Skipped, ", "))
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"opensearch-cli/entity"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	UpdateProfileCommandName = "update"
+	FlagProfileCACert        = "ca-cert"
+	FlagProfileClientCert    = "client-cert"
+	FlagProfileClientKey     = "client-key"
+)
+
+//updateProfileCmd updates settings of an existing profile, only fields provided as flags are changed
+var updateProfileCmd = &cobra.Command{
+	Use:   UpdateProfileCommandName + " profile_name [flags]",
+	Short: "Update profile",
+	Long: "Update settings of an existing named profile. Only settings provided as flags are changed.\n" +
+		"If --auth-type is provided, opensearch-cli asks for credentials required by the new authentication type.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := updateProfile(cmd, args[0]); err != nil {
+			DisplayError(err, UpdateProfileCommandName)
+			return
+		}
+		fmt.Println("Profile updated successfully.")
+	},
+}
+
+func init() {
+	profileCommand.AddCommand(updateProfileCmd)
+	updateProfileCmd.Flags().StringP(FlagProfileCreateEndpoint, "e", "", "Endpoint or host of the cluster")
+	updateProfileCmd.Flags().StringP(FlagProfileCreateAuthType, "a", "", "Authentication type. Options are disabled, basic, cert, token, api-key, credential-process and aws-iam.\n"+
+		"opensearch-cli asks for additional information based on your choice of authentication type.")
+	updateProfileCmd.Flags().IntP(FlagProfileMaxRetry, "m", 3, "Maximum retry attempts allowed if transient problems occur")
+	updateProfileCmd.Flags().Int64P(FlagProfileTimeout, "t", 10, "Maximum time allowed for connection in seconds")
+	updateProfileCmd.Flags().String(FlagProfileCACert, "", "Certificate Authority's (CA) certificate file path, provide empty value to remove it")
+	updateProfileCmd.Flags().String(FlagProfileClientCert, "", "Client certificate file path, provide empty value to remove it")
+	updateProfileCmd.Flags().String(FlagProfileClientKey, "", "Client key file path, provide empty value to remove it")
+	addTLSFlags(updateProfileCmd)
+	addEndpointFlags(updateProfileCmd)
+	addRetryFlags(updateProfileCmd)
+	updateProfileCmd.Flags().BoolP(FlagProfileHelp, "h", false, "Help for "+UpdateProfileCommandName)
+}
+
+//updateProfile applies changed flags on profile of config file and saves it, profile of project config file
+//cannot be updated
+func updateProfile(cmd *cobra.Command, name string) error {
+	profileController, err := GetProfileController()
+	if err != nil {
+		return err
+	}
+	p, err := profileController.GetWritableProfile(name)
+	if err != nil {
+		return err
+	}
+	if err = applyProfileFlags(cmd, &p); err != nil {
+		return err
+	}
+	return profileController.UpdateProfile(p)
+}
+
+//applyProfileFlags updates profile fields only for flags provided by user
+func applyProfileFlags(cmd *cobra.Command, p *entity.Profile) error {
+	flags := cmd.Flags()
+	if flags.Changed(FlagProfileCreateEndpoint) {
+		p.Endpoint, _ = flags.GetString(FlagProfileCreateEndpoint)
+	}
+	if flags.Changed(FlagProfileMaxRetry) {
+		maxRetry, _ := flags.GetInt(FlagProfileMaxRetry)
+		p.MaxRetry = &maxRetry
+	}
+	if flags.Changed(FlagProfileTimeout) {
+		timeout, _ := flags.GetInt64(FlagProfileTimeout)
+		p.Timeout = &timeout
+	}
+	if err := applyEndpointFlags(cmd, p); err != nil {
+		return err
+	}
+	applyRetryFlags(cmd, p)
+	if err := validateRetry(p.Retry); err != nil {
+		return err
+	}
+	if flags.Changed(FlagProfileCreateAuthType) {
+		authType, _ := flags.GetString(FlagProfileCreateAuthType)
+		p.UserName = ""
+		p.Password = ""
+		p.Token = nil
+		p.APIKey = ""
+		p.CredentialProcess = ""
+		p.AWS = nil
+		if p.Certificate != nil {
+			//only client certificate is used for authentication, trust settings of cluster are kept
+			trust := *p.Certificate
+			trust.ClientCertificateFilePath = nil
+			trust.ClientKeyFilePath = nil
+			p.Certificate = &trust
+		}
+		if err := getAuthDetails(GetPrompter(), authType, p); err != nil {
+			return err
+		}
+	}
+	setCertificatePath(cmd, p, FlagProfileCACert, func(t *entity.Trust) **string { return &t.CAFilePath })
+	setCertificatePath(cmd, p, FlagProfileClientCert, func(t *entity.Trust) **string { return &t.ClientCertificateFilePath })
+	setCertificatePath(cmd, p, FlagProfileClientKey, func(t *entity.Trust) **string { return &t.ClientKeyFilePath })
+	return applyTLSFlags(cmd, p)
+}
+
+//setCertificatePath sets certificate path if flag is provided, empty value removes the path
+func setCertificatePath(cmd *cobra.Command, p *entity.Profile, flagName string, field func(*entity.Trust) **string) {
+	if !cmd.Flags().Changed(flagName) {
+		return
+	}
+	value, _ := cmd.Flags().GetString(flagName)
+	if p.Certificate == nil {
+		p.Certificate = &entity.Trust{}
+	}
+	if len(value) < 1 {
+		*field(p.Certificate) = nil
+		return
+	}
+	*field(p.Certificate) = &value
+}
@@ -26,11 +26,20 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"opensearch-cli/client"
 	"opensearch-cli/entity"
+	"opensearch-cli/environment"
+	"opensearch-cli/mapper"
+	"opensearch-cli/prompt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 )
@@ -40,9 +49,20 @@ const (
 	defaultConfigFileName = "config"
 	flagConfig            = "config"
 	flagProfileName       = "profile"
+	flagAssumeYes         = "yes"
+	flagNoInput           = "no-input"
+	flagInsecure          = "insecure"
+	flagEndpoint          = "endpoint"
+	flagUser              = "user"
+	flagDebug             = "debug"
+	flagTrace             = "trace"
+	flagRecord            = "record"
+	flagReplay            = "replay"
+	adHocProfileName      = "ad-hoc"
 	FolderPermission      = 0700 // only owner can read, write and execute
 	FilePermission        = 0600 // only owner can read and write
 	ConfigEnvVarName      = "OPENSEARCH_CLI_CONFIG"
+	ProjectConfigFileName = ".opensearch-cli.yaml"
 	RootCommandName       = "opensearch-cli"
 	version               = "1.0.0"
 )
@@ -56,16 +76,33 @@ var rootCommand = &cobra.Command{
 	Use:     RootCommandName,
 	Short:   "opensearch-cli is a unified command line interface for managing OpenSearch clusters",
 	Version: buildVersionString(),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString(flagOutput)
+		if err != nil {
+			return err
+		}
+		if err = validateOutputFormat(format); err != nil {
+			return err
+		}
+		errorFormat, err := cmd.Flags().GetString(flagErrorFormat)
+		if err != nil {
+			return err
+		}
+		return validateErrorFormat(errorFormat)
+	},
 }
 
 func GetRoot() *cobra.Command {
 	return rootCommand
 }
 
-// Execute executes the root command.
+// Execute executes the root command. Returned error can be mapped to process exit code using ExitCode
 func Execute() error {
-	err := rootCommand.Execute()
-	return err
+	commandError = nil
+	if err := rootCommand.Execute(); err != nil {
+		return reportUsageError(err)
+	}
+	return commandError
 }
 
 func GetDefaultConfigFilePath() string {
@@ -91,6 +128,30 @@ func init() {
 	configFilePath := GetDefaultConfigFilePath()
 	rootCommand.PersistentFlags().StringP(flagConfig, "c", "", fmt.Sprintf("Configuration file for opensearch-cli, default is %s", configFilePath))
 	rootCommand.PersistentFlags().StringP(flagProfileName, "p", "", "Use a specific profile from your configuration file")
+	rootCommand.PersistentFlags().String(flagOutput, "", fmt.Sprintf(
+		"Output format, options are %s. If not provided, every command uses its own default format",
+		strings.Join(outputFormats, ", ")))
+	rootCommand.PersistentFlags().BoolP(flagAssumeYes, "y", false, "Assume yes for all confirmation prompts and run non-interactively.\n"+
+		"You can also enable this by setting the "+environment.OPENSEARCH_ASSUME_YES+" environment variable to true.")
+	rootCommand.PersistentFlags().Bool(flagNoInput, false, "Never prompt for user input, commands which require input fail instead.\n"+
+		"You can also enable this by setting the "+environment.OPENSEARCH_NO_INPUT+" environment variable to true.")
+	rootCommand.PersistentFlags().String(flagEndpoint, "", "Endpoint of the cluster, overrides profile's endpoint.\n"+
+		"You can also provide this by setting the "+environment.OPENSEARCH_ENDPOINT+" environment variable")
+	rootCommand.PersistentFlags().String(flagUser, "", "User for HTTP basic authentication, overrides profile's credentials.\n"+
+		"Password is read from the "+environment.OPENSEARCH_PASSWORD+" environment variable, or prompted if it is not set.\n"+
+		"You can also provide user by setting the "+environment.OPENSEARCH_USER+" environment variable")
+	rootCommand.PersistentFlags().Bool(flagInsecure, false, "Skip verification of cluster's TLS certificate, overrides profile's certificate settings.\n"+
+		"This makes connection vulnerable to man-in-the-middle attacks, use it only for testing")
+	rootCommand.PersistentFlags().String(flagErrorFormat, errorFormatText, fmt.Sprintf(
+		"Format of error message, options are %s and %s. If %s, error is written on stderr along with status code, url, type and reason from cluster",
+		errorFormatText, errorFormatJSON, errorFormatJSON))
+	rootCommand.PersistentFlags().Bool(flagDebug, false, "Log method, url, headers, body sizes, status, retries and timings of every request on stderr.\n"+
+		"Credentials and signatures are redacted")
+	rootCommand.PersistentFlags().Bool(flagTrace, false, "Log everything logged by --debug along with request and response bodies on stderr.\n"+
+		"Secret fields of JSON bodies, like password or token, are redacted. Other data in bodies is logged as it is")
+	rootCommand.PersistentFlags().String(flagRecord, "", "Save every request and its response as fixture file in given folder, secrets in headers\n"+
+		"and secret fields of JSON bodies, like password or token, are scrubbed. Other data in bodies is saved as it is")
+	rootCommand.PersistentFlags().String(flagReplay, "", "Serve responses from fixture files saved by --record in given folder, instead of connecting to cluster")
 	rootCommand.Flags().BoolP("version", "v", false, "Version for opensearch-cli")
 	rootCommand.Flags().BoolP("help", "h", false, "Help for opensearch-cli")
 }
@@ -110,6 +171,43 @@ func GetConfigFilePath(configFlagValue string) (string, error) {
 	return GetDefaultConfigFilePath(), nil
 }
 
+//findProjectConfigFile looks for project config file in current directory and its parents, nearest file is returned
+func findProjectConfigFile() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	return findFileInParents(dir, ProjectConfigFileName)
+}
+
+//findFileInParents looks for file by name in dir, and then in parents of dir up to root
+func findFileInParents(dir string, name string) (string, bool) {
+	for {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+//isSameFile returns true if both paths refer to same file
+func isSameFile(path string, other string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	otherInfo, err := os.Stat(other)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info, otherInfo)
+}
+
 // createDefaultConfigFolderIfNotExists creates default config file along with folder if
 // it doesn't exists
 func createDefaultConfigFileIfNotExists() error {
@@ -143,30 +241,192 @@ func isExists(path string) bool {
 	return true
 }
 
-// DisplayError prints command name and error on console and exists as well.
+// DisplayError prints command name and error on console, error is also recorded to decide exit code.
+// If --error-format is json, error envelope is written on stderr instead.
 func DisplayError(err error, cmdName string) {
-	if err != nil {
-		fmt.Println(cmdName, "Command failed.")
-		fmt.Println("Reason:", err)
+	if err == nil {
+		return
+	}
+	commandError = err
+	if isJSONErrorFormat() {
+		printErrorEnvelope(os.Stderr, err, cmdName)
+		return
 	}
+	fmt.Println(cmdName, "Command failed.")
+	fmt.Println("Reason:", err)
 }
 
-// GetProfile gets profile details for current execution
-func GetProfile() (*entity.Profile, error) {
-	p, err := GetProfileController()
+//newClient returns client which traces requests on stderr if --debug or --trace is provided, and
+//records or replays requests if --record or --replay is provided
+func newClient() (*client.Client, error) {
+	tripper, err := getRoundTripper()
 	if err != nil {
 		return nil, err
 	}
-	profileFlagValue, err := rootCommand.PersistentFlags().GetString(flagProfileName)
+	c, err := client.New(tripper)
 	if err != nil {
 		return nil, err
 	}
-	profile, ok, err := p.GetProfileForExecution(profileFlagValue)
+	c.EnableTrace(os.Stderr, getTraceLevel())
+	return c, nil
+}
+
+//getRoundTripper returns recorder or replayer requested by --record and --replay flags, nil if neither is provided
+func getRoundTripper() (http.RoundTripper, error) {
+	recordDir, _ := rootCommand.PersistentFlags().GetString(flagRecord)
+	replayDir, _ := rootCommand.PersistentFlags().GetString(flagReplay)
+	switch {
+	case len(recordDir) > 0 && len(replayDir) > 0:
+		return nil, fmt.Errorf("--%s and --%s cannot be used together", flagRecord, flagReplay)
+	case len(recordDir) > 0:
+		return client.NewRecorder(recordDir, nil)
+	case len(replayDir) > 0:
+		return client.NewReplayer(replayDir)
+	}
+	return nil, nil
+}
+
+//getTraceLevel returns trace level requested by --debug and --trace flags
+func getTraceLevel() client.TraceLevel {
+	if trace, _ := rootCommand.PersistentFlags().GetBool(flagTrace); trace {
+		return client.TraceBodies
+	}
+	if debug, _ := rootCommand.PersistentFlags().GetBool(flagDebug); debug {
+		return client.TraceHeaders
+	}
+	return client.TraceOff
+}
+
+//stdinPrompter reads stdin for every prompt of the process, since its reader may buffer input beyond the line
+//which is read, a new reader would lose answers piped for later prompts
+var (
+	stdinPrompter     *prompt.Prompter
+	stdinPrompterOnce sync.Once
+)
+
+// GetPrompter returns prompter which honors --yes and --no-input flags, or corresponding environment variables
+func GetPrompter() *prompt.Prompter {
+	stdinPrompterOnce.Do(func() {
+		stdinPrompter = prompt.New(os.Stdin, false, false)
+	})
+	return stdinPrompter.WithModes(isEnabled(flagAssumeYes, environment.OPENSEARCH_ASSUME_YES), isEnabled(flagNoInput, environment.OPENSEARCH_NO_INPUT))
+}
+
+//isEnabled checks whether boolean flag is set, if not, value is read from environment variable
+func isEnabled(flagName string, envVariable string) bool {
+	if enabled, err := rootCommand.PersistentFlags().GetBool(flagName); err == nil && enabled {
+		return true
+	}
+	if val, ok := os.LookupEnv(envVariable); ok {
+		//ignore error from invalid boolean value
+		enabled, _ := strconv.ParseBool(val)
+		return enabled
+	}
+	return false
+}
+
+// GetProfile gets profile details for current execution. Settings are resolved in the following order of precedence:
+// global flags, environment variables, named profile (--profile or OPENSEARCH_PROFILE) and default profile.
+// If config file doesn't exist, or doesn't have default profile, endpoint from flag or environment variable is enough
+// to run the command
+func GetProfile() (*entity.Profile, error) {
+	profileFlagValue, err := rootCommand.PersistentFlags().GetString(flagProfileName)
 	if err != nil {
 		return nil, err
 	}
+	profile, ok, err := getProfileForExecution(profileFlagValue)
+	//config file is not required to run without profile, but profile which exists and is invalid is never ignored
+	if err != nil && (ok || !errors.Is(err, os.ErrNotExist) || !canRunWithoutProfile(profileFlagValue)) {
+		return nil, err
+	}
 	if !ok {
-		return nil, fmt.Errorf("no profile found for execution. Try %s %s --help for more information", RootCommandName, ProfileCommandName)
+		if !canRunWithoutProfile(profileFlagValue) {
+			return nil, fmt.Errorf("no profile found for execution. Try %s %s --help for more information, or set %s",
+				RootCommandName, ProfileCommandName, environment.OPENSEARCH_ENDPOINT)
+		}
+		profile = entity.Profile{Name: adHocProfileName}
+	}
+	if err = applyOverrides(&profile); err != nil {
+		return nil, err
 	}
 	return &profile, nil
 }
+
+//getProfileForExecution gets profile by name from config file
+func getProfileForExecution(name string) (entity.Profile, bool, error) {
+	p, err := GetProfileController()
+	if err != nil {
+		return entity.Profile{}, false, err
+	}
+	return p.GetProfileForExecution(name)
+}
+
+//canRunWithoutProfile checks whether command can run using only flags and environment variables,
+//which is possible if user didn't ask for a profile and provided endpoint
+func canRunWithoutProfile(profileFlagValue string) bool {
+	if len(profileFlagValue) > 0 {
+		return false
+	}
+	if _, ok := os.LookupEnv(environment.OPENSEARCH_PROFILE); ok {
+		return false
+	}
+	endpoint, _ := lookupOverride(flagEndpoint, environment.OPENSEARCH_ENDPOINT)
+	return len(endpoint) > 0
+}
+
+//lookupOverride returns value from flag if provided, else, from environment variable
+func lookupOverride(flagName string, envVariable string) (string, bool) {
+	if flag := rootCommand.PersistentFlags().Lookup(flagName); flag != nil && flag.Changed {
+		return flag.Value.String(), true
+	}
+	if val, ok := os.LookupEnv(envVariable); ok && len(val) > 0 {
+		return val, false
+	}
+	return "", false
+}
+
+//applyOverrides overrides profile settings with values from environment variables and global flags
+func applyOverrides(profile *entity.Profile) error {
+	if endpoint, _ := lookupOverride(flagEndpoint, environment.OPENSEARCH_ENDPOINT); len(endpoint) > 0 {
+		profile.Endpoint = endpoint
+		//endpoint provided by flag or environment variable is the only node used
+		profile.Endpoints = nil
+	}
+	password, hasPassword := os.LookupEnv(environment.OPENSEARCH_PASSWORD)
+	user, fromFlag := lookupOverride(flagUser, environment.OPENSEARCH_USER)
+	switch {
+	case len(user) > 0 && user != profile.UserName:
+		//credentials of profile belong to another user, hence, use only user's password
+		*profile = withoutCredentials(*profile)
+		profile.UserName = user
+		if !hasPassword && fromFlag {
+			var err error
+			if password, err = GetPrompter().MaskedText("Password", checkInputIsNotEmpty); err != nil {
+				return err
+			}
+		}
+		profile.Password = password
+	case hasPassword && len(profile.UserName) > 0:
+		profile.Password = password
+	}
+	if insecure, _ := rootCommand.PersistentFlags().GetBool(flagInsecure); insecure {
+		trust := entity.Trust{}
+		if profile.Certificate != nil {
+			trust = *profile.Certificate
+		}
+		trust.Insecure = mapper.BoolToBoolPtr(true)
+		profile.Certificate = &trust
+	}
+	return nil
+}
+
+//withoutCredentials returns copy of profile without any credentials
+func withoutCredentials(profile entity.Profile) entity.Profile {
+	profile.UserName = ""
+	profile.Password = ""
+	profile.Token = nil
+	profile.APIKey = ""
+	profile.CredentialProcess = ""
+	profile.AWS = nil
+	return profile
+}
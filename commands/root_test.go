@@ -28,10 +28,14 @@ package commands
 import (
 	"fmt"
 	"io/ioutil"
+	"opensearch-cli/client"
 	"opensearch-cli/entity"
+	"opensearch-cli/environment"
+	"opensearch-cli/mapper"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -167,3 +171,217 @@ func TestGetProfile(t *testing.T) {
 		assert.EqualError(t, err, fmt.Sprintf("permissions 750 for '%s' are too open. It is required that your config file is NOT accessible by others", filePath), "unexpected error")
 	})
 }
+
+func TestApplyOverrides(t *testing.T) {
+	t.Run("insecure overrides certificate settings", func(t *testing.T) {
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagInsecure, "true"))
+		defer func() {
+			assert.NoError(t, rootCommand.PersistentFlags().Set(flagInsecure, "false"))
+		}()
+		caPath := "ca.pem"
+		profile := entity.Profile{Name: "default", Certificate: &entity.Trust{CAFilePath: &caPath}}
+		assert.NoError(t, applyOverrides(&profile))
+		assert.EqualValues(t, &entity.Trust{CAFilePath: &caPath, Insecure: mapper.BoolToBoolPtr(true)}, profile.Certificate)
+	})
+	t.Run("flags take precedence over environment variables and profile", func(t *testing.T) {
+		defer resetOverrides(t)
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_ENDPOINT, "https://env:9200"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_USER, "env-user"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_PASSWORD, "env-password"))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagEndpoint, "https://flag:9200"))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagUser, "flag-user"))
+		profile := entity.Profile{Name: "default", Endpoint: "https://profile:9200", APIKey: "api-key"}
+		assert.NoError(t, applyOverrides(&profile))
+		assert.EqualValues(t, entity.Profile{Name: "default", Endpoint: "https://flag:9200", UserName: "flag-user", Password: "env-password"}, profile)
+	})
+	t.Run("environment variables take precedence over profile", func(t *testing.T) {
+		defer resetOverrides(t)
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_ENDPOINT, "https://env:9200"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_PASSWORD, "env-password"))
+		profile := entity.Profile{Name: "default", Endpoint: "https://profile:9200", UserName: "admin", Password: "admin"}
+		assert.NoError(t, applyOverrides(&profile))
+		assert.EqualValues(t, entity.Profile{Name: "default", Endpoint: "https://env:9200", UserName: "admin", Password: "env-password"}, profile)
+	})
+	t.Run("profile is unchanged without flags", func(t *testing.T) {
+		profile := entity.Profile{Name: "default"}
+		assert.NoError(t, applyOverrides(&profile))
+		assert.Nil(t, profile.Certificate)
+	})
+}
+
+//resetOverrides clears environment variables and global flags which override profile
+func resetOverrides(t *testing.T) {
+	for _, name := range []string{environment.OPENSEARCH_ENDPOINT, environment.OPENSEARCH_USER, environment.OPENSEARCH_PASSWORD} {
+		assert.NoError(t, os.Unsetenv(name))
+	}
+	for _, name := range []string{flagEndpoint, flagUser, flagProfileName} {
+		flag := rootCommand.PersistentFlags().Lookup(name)
+		assert.NoError(t, flag.Value.Set(""))
+		flag.Changed = false
+	}
+}
+
+func TestGetProfileWithoutProfile(t *testing.T) {
+	profileFile, err := createTempConfigFile("testdata/config.yaml")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Remove(profileFile.Name()))
+	}()
+	t.Run("environment variables are used if profile doesn't exist", func(t *testing.T) {
+		defer resetOverrides(t)
+		resetOverrides(t)
+		emptyConfig, err := ioutil.TempFile("", "empty-config")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.Remove(emptyConfig.Name()))
+		}()
+		assert.NoError(t, emptyConfig.Chmod(FilePermission))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagConfig, emptyConfig.Name()))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_ENDPOINT, "https://env:9200"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_USER, "env-user"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_PASSWORD, "env-password"))
+		actual, err := GetProfile()
+		assert.NoError(t, err)
+		assert.EqualValues(t, entity.Profile{Name: adHocProfileName, Endpoint: "https://env:9200", UserName: "env-user", Password: "env-password"}, *actual)
+	})
+	t.Run("named profile is required if requested", func(t *testing.T) {
+		defer resetOverrides(t)
+		resetOverrides(t)
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagConfig, profileFile.Name()))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagProfileName, "missing"))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_ENDPOINT, "https://env:9200"))
+		_, err := GetProfile()
+		assert.EqualError(t, err, "profile 'missing' does not exist")
+	})
+	t.Run("config file is not required", func(t *testing.T) {
+		defer resetOverrides(t)
+		resetOverrides(t)
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagConfig, filepath.Join(t.TempDir(), "config.yaml")))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_ENDPOINT, "https://env:9200"))
+		actual, err := GetProfile()
+		assert.NoError(t, err)
+		assert.EqualValues(t, entity.Profile{Name: adHocProfileName, Endpoint: "https://env:9200"}, *actual)
+	})
+	t.Run("default profile which cannot be decrypted is not ignored", func(t *testing.T) {
+		defer resetOverrides(t)
+		resetOverrides(t)
+		encryptedConfig := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, ioutil.WriteFile(encryptedConfig, []byte("profiles:\n  - name: default\n    endpoint: https://localhost:9200\n"+
+			"    user: admin\n    password: enc:v1:uVHgmkuFo4hQmIlSjl7tss/l+i5FnkD/LYFtbm6xph1AfCvX9iFI77ouQXOT/3o+Zw==\n"), FilePermission))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagConfig, encryptedConfig))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagEndpoint, "https://flag:9200"))
+		_, err := GetProfile()
+		assert.EqualError(t, err, "failed to decrypt secrets of profile 'default' due to: encryption key is not found, "+
+			"set OPENSEARCH_CLI_KEY_FILE or OPENSEARCH_CLI_PASSPHRASE")
+	})
+	t.Run("endpoint flag overrides default profile", func(t *testing.T) {
+		defer resetOverrides(t)
+		resetOverrides(t)
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagConfig, profileFile.Name()))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagEndpoint, "https://flag:9200"))
+		actual, err := GetProfile()
+		assert.NoError(t, err)
+		assert.EqualValues(t, entity.Profile{Name: "default", Endpoint: "https://flag:9200", UserName: "default", Password: "admin"}, *actual)
+	})
+}
+
+func TestFindFileInParents(t *testing.T) {
+	root, err := ioutil.TempDir("", "project")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(root))
+	}()
+	nested := filepath.Join(root, "service", "detectors")
+	assert.NoError(t, os.MkdirAll(nested, FolderPermission))
+	t.Run("file doesn't exist", func(t *testing.T) {
+		_, ok := findFileInParents(nested, ProjectConfigFileName)
+		assert.False(t, ok)
+	})
+	t.Run("nearest file is found", func(t *testing.T) {
+		projectFile := filepath.Join(root, ProjectConfigFileName)
+		assert.NoError(t, ioutil.WriteFile(projectFile, []byte("profiles: []"), FilePermission))
+		path, ok := findFileInParents(nested, ProjectConfigFileName)
+		assert.True(t, ok)
+		assert.EqualValues(t, projectFile, path)
+
+		serviceFile := filepath.Join(root, "service", ProjectConfigFileName)
+		assert.NoError(t, ioutil.WriteFile(serviceFile, []byte("profiles: []"), FilePermission))
+		path, ok = findFileInParents(nested, ProjectConfigFileName)
+		assert.True(t, ok)
+		assert.EqualValues(t, serviceFile, path)
+	})
+	t.Run("directory is ignored", func(t *testing.T) {
+		assert.NoError(t, os.Mkdir(filepath.Join(nested, ProjectConfigFileName), FolderPermission))
+		path, ok := findFileInParents(nested, ProjectConfigFileName)
+		assert.True(t, ok)
+		assert.EqualValues(t, filepath.Join(root, "service", ProjectConfigFileName), path)
+	})
+}
+
+func TestGetTraceLevel(t *testing.T) {
+	reset := func() {
+		for _, name := range []string{flagDebug, flagTrace} {
+			flag := rootCommand.PersistentFlags().Lookup(name)
+			assert.NoError(t, flag.Value.Set("false"))
+			flag.Changed = false
+		}
+	}
+	defer reset()
+	assert.EqualValues(t, client.TraceOff, getTraceLevel())
+	assert.NoError(t, rootCommand.PersistentFlags().Set(flagDebug, "true"))
+	assert.EqualValues(t, client.TraceHeaders, getTraceLevel())
+	assert.NoError(t, rootCommand.PersistentFlags().Set(flagTrace, "true"))
+	assert.EqualValues(t, client.TraceBodies, getTraceLevel())
+}
+
+func TestGetPrompterSharesStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	_, err = w.WriteString("pw\ny\n")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	stdin := os.Stdin
+	os.Stdin = r
+	stdinPrompterOnce = sync.Once{}
+	defer func() {
+		os.Stdin = stdin
+		stdinPrompterOnce = sync.Once{}
+		assert.NoError(t, r.Close())
+	}()
+	password, err := GetPrompter().MaskedText("Password", nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "pw", password)
+	proceed, err := GetPrompter().Confirm("Do you want to proceed? Y/N ")
+	assert.NoError(t, err)
+	assert.True(t, proceed, "answer buffered by first prompt is read by second prompt")
+}
+
+func TestGetRoundTripper(t *testing.T) {
+	reset := func() {
+		for _, name := range []string{flagRecord, flagReplay} {
+			flag := rootCommand.PersistentFlags().Lookup(name)
+			assert.NoError(t, flag.Value.Set(""))
+			flag.Changed = false
+		}
+	}
+	defer reset()
+	t.Run("default transport", func(t *testing.T) {
+		tripper, err := getRoundTripper()
+		assert.NoError(t, err)
+		assert.Nil(t, tripper)
+	})
+	t.Run("record", func(t *testing.T) {
+		defer reset()
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagRecord, t.TempDir()))
+		tripper, err := getRoundTripper()
+		assert.NoError(t, err)
+		assert.IsType(t, &client.Recorder{}, tripper)
+	})
+	t.Run("record and replay", func(t *testing.T) {
+		defer reset()
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagRecord, t.TempDir()))
+		assert.NoError(t, rootCommand.PersistentFlags().Set(flagReplay, t.TempDir()))
+		_, err := getRoundTripper()
+		assert.EqualError(t, err, "--record and --replay cannot be used together")
+	})
+}
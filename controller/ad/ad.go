@@ -33,7 +33,7 @@ import (
 	"opensearch-cli/gateway/ad"
 	"opensearch-cli/mapper"
 	admapper "opensearch-cli/mapper/ad"
-	"os"
+	"opensearch-cli/prompt"
 	"strings"
 
 	"github.com/cheggaaa/pb/v3"
@@ -58,15 +58,20 @@ type Controller interface {
 }
 
 type controller struct {
-	reader     io.Reader
+	prompter   *prompt.Prompter
 	gateway    ad.Gateway
 	openSearch platform.Controller
 }
 
-//New returns new Controller instance
+//New returns new Controller instance which reads confirmation from reader
 func New(reader io.Reader, openSearch platform.Controller, gateway ad.Gateway) Controller {
+	return NewWithPrompter(prompt.New(reader, false, false), openSearch, gateway)
+}
+
+//NewWithPrompter returns new Controller instance which uses prompter for confirmation
+func NewWithPrompter(prompter *prompt.Prompter, openSearch platform.Controller, gateway ad.Gateway) Controller {
 	return &controller{
-		reader,
+		prompter,
 		gateway,
 		openSearch,
 	}
@@ -112,31 +117,13 @@ func (c controller) StopDetector(ctx context.Context, ID string) error {
 	return nil
 }
 
-func (c controller) askForConfirmation(message *string) bool {
+//askForConfirmation asks user to confirm before proceeding, returns error if user input is not available
+func (c controller) askForConfirmation(message *string) (bool, error) {
 
 	if message == nil {
-		return true
-	}
-	if len(*message) > 0 {
-		fmt.Print(*message)
-	}
-
-	var response string
-	_, err := fmt.Fscanln(c.reader, &response)
-	if err != nil {
-		//Exit if for some reason, we are not able to accept user input
-		fmt.Printf("failed to accept value from user due to %s", err)
-		os.Exit(1)
-	}
-	switch strings.ToLower(response) {
-	case "y", "yes":
-		return true
-	case "n", "no":
-		return false
-	default:
-		fmt.Printf("please type (y)es or (n)o and then press enter:")
-		return c.askForConfirmation(mapper.StringToStringPtr(""))
+		return true, nil
 	}
+	return c.prompter.Confirm(*message)
 }
 
 //DeleteDetector deletes detector based on DetectorID, if force is enabled, it stops before deletes
@@ -146,7 +133,8 @@ func (c controller) DeleteDetector(ctx context.Context, id string, interactive b
 	}
 	proceed := true
 	if interactive {
-		proceed = c.askForConfirmation(
+		var err error
+		proceed, err = c.askForConfirmation(
 			mapper.StringToStringPtr(
 				fmt.Sprintf(
 					"opensearch-cli will delete detector: %s . Do you want to proceed? Y/N ",
@@ -154,6 +142,9 @@ func (c controller) DeleteDetector(ctx context.Context, id string, interactive b
 				),
 			),
 		)
+		if err != nil {
+			return err
+		}
 	}
 	if !proceed {
 		return nil
@@ -323,7 +314,7 @@ func (c controller) CreateMultiEntityAnomalyDetector(ctx context.Context, reques
 	}
 	proceed := true
 	if interactive {
-		proceed = c.askForConfirmation(
+		proceed, err = c.askForConfirmation(
 			mapper.StringToStringPtr(
 				fmt.Sprintf(
 					"opensearch-cli will create %d detector(s). Do you want to proceed? please type (y)es or (n)o and then press enter:",
@@ -331,6 +322,9 @@ func (c controller) CreateMultiEntityAnomalyDetector(ctx context.Context, reques
 				),
 			),
 		)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if !proceed {
 		return nil, nil
@@ -412,11 +406,14 @@ func (c controller) getDetectors(ctx context.Context, method string, pattern str
 		fmt.Println(detector.Name)
 	}
 
-	proceed := c.askForConfirmation(
+	proceed, err := c.askForConfirmation(
 		mapper.StringToStringPtr(
 			fmt.Sprintf("opensearch-cli will %s above matched detector(s). Do you want to proceed? Y/N ", method),
 		),
 	)
+	if err != nil {
+		return nil, err
+	}
 	if !proceed {
 		return nil, nil
 	}
@@ -456,7 +453,11 @@ func (c controller) processDetectorByAction(ctx context.Context, pattern string,
 	for _, detector := range failedDetectors {
 		fmt.Println(detector)
 	}
-	return nil
+	return &entity.PartialFailureError{
+		Action: action,
+		Total:  len(matchedDetectors),
+		Failed: failedDetectors,
+	}
 }
 
 //StartDetectorByName starts detector based on name pattern. It first calls SearchDetectorByName and then
@@ -499,13 +500,18 @@ func (c controller) DeleteDetectorByName(ctx context.Context, name string, force
 	if bar != nil {
 		bar.Finish()
 	}
-	if len(failedDetectors) > 0 {
-		fmt.Printf("failed to delete %d following detector(s)\n", len(failedDetectors))
-		for _, detector := range failedDetectors {
-			fmt.Println(detector)
-		}
+	if len(failedDetectors) < 1 {
+		return nil
+	}
+	fmt.Printf("failed to delete %d following detector(s)\n", len(failedDetectors))
+	for _, detector := range failedDetectors {
+		fmt.Println(detector)
+	}
+	return &entity.PartialFailureError{
+		Action: "delete",
+		Total:  len(matchedDetectors),
+		Failed: failedDetectors,
 	}
-	return nil
 }
 
 //GetDetectorsByName get detector based on name pattern. It first calls SearchDetectorByName and then
@@ -555,7 +561,7 @@ func (c controller) UpdateDetector(ctx context.Context, input entity.UpdateDetec
 				"new version for detector is available. Please fetch latest version and then merge your changes")
 		}
 	}
-	proceed := c.askForConfirmation(
+	proceed, err := c.askForConfirmation(
 		mapper.StringToStringPtr(
 			fmt.Sprintf(
 				"opensearch-cli will update detector: %s . Do you want to proceed? Y/N ",
@@ -563,6 +569,9 @@ func (c controller) UpdateDetector(ctx context.Context, input entity.UpdateDetec
 			),
 		),
 	)
+	if err != nil {
+		return err
+	}
 	if !proceed {
 		return nil
 	}
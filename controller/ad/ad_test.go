@@ -33,6 +33,7 @@ import (
 	entity "opensearch-cli/entity/ad"
 	gateway "opensearch-cli/gateway/ad/mocks"
 	"opensearch-cli/mapper"
+	"opensearch-cli/prompt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -375,6 +376,41 @@ func TestController_DeleteDetector(t *testing.T) {
 		err := ctrl.DeleteDetector(ctx, mockDetectorID, true, false)
 		assert.NoError(t, err)
 	})
+	t.Run("no user input", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ctx := context.Background()
+		mockADGateway := gateway.NewMockGateway(mockCtrl)
+		mockESController := mockController.NewMockController(mockCtrl)
+		var stdin bytes.Buffer
+		ctrl := New(&stdin, mockESController, mockADGateway)
+		err := ctrl.DeleteDetector(ctx, mockDetectorID, true, false)
+		assert.IsType(t, &prompt.InputUnavailableError{}, err)
+	})
+	t.Run("assume yes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ctx := context.Background()
+		mockADGateway := gateway.NewMockGateway(mockCtrl)
+		mockESController := mockController.NewMockController(mockCtrl)
+		mockADGateway.EXPECT().DeleteDetector(ctx, mockDetectorID).Return(nil)
+		var stdin bytes.Buffer
+		ctrl := NewWithPrompter(prompt.New(&stdin, true, true), mockESController, mockADGateway)
+		err := ctrl.DeleteDetector(ctx, mockDetectorID, true, false)
+		assert.NoError(t, err)
+	})
+	t.Run("prompts disabled", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ctx := context.Background()
+		mockADGateway := gateway.NewMockGateway(mockCtrl)
+		mockESController := mockController.NewMockController(mockCtrl)
+		var stdin bytes.Buffer
+		stdin.Write([]byte("yes\n"))
+		ctrl := NewWithPrompter(prompt.New(&stdin, false, true), mockESController, mockADGateway)
+		err := ctrl.DeleteDetector(ctx, mockDetectorID, true, false)
+		assert.EqualError(t, err, "user input is required for 'opensearch-cli will delete detector: m4ccEnIBTXsGi3mvMt9p . Do you want to proceed? Y/N', but prompts are disabled")
+	})
 }
 
 func TestController_CreateMultiEntityAnomalyDetector(t *testing.T) {
@@ -545,7 +581,8 @@ func TestController_StopDetectorByName(t *testing.T) {
 		mockESController := mockController.NewMockController(mockCtrl)
 		ctrl := New(&stdin, mockESController, mockADGateway)
 		err := ctrl.StopDetectorByName(ctx, "detector", false)
-		assert.NoError(t, err)
+		assert.EqualError(t, err, "failed to stop 1/1 detector(s)")
+		assert.IsType(t, &entity.PartialFailureError{}, err)
 	})
 	t.Run("search detector gateway failed", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
@@ -601,7 +638,8 @@ func TestController_StartDetectorByName(t *testing.T) {
 		mockESController := mockController.NewMockController(mockCtrl)
 		ctrl := New(&stdin, mockESController, mockADGateway)
 		err := ctrl.StartDetectorByName(ctx, "detector", false)
-		assert.NoError(t, err)
+		assert.EqualError(t, err, "failed to start 1/1 detector(s)")
+		assert.IsType(t, &entity.PartialFailureError{}, err)
 	})
 	t.Run("search detector gateway failed", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
@@ -655,7 +693,8 @@ func TestController_DeleteDetectorByName(t *testing.T) {
 		stdin.Write([]byte("yes\n"))
 		ctrl := New(&stdin, mockESController, mockADGateway)
 		err := ctrl.DeleteDetectorByName(ctx, mockDetectorName, false, false)
-		assert.NoError(t, err)
+		assert.EqualError(t, err, "failed to delete 1/1 detector(s)")
+		assert.IsType(t, &entity.PartialFailureError{}, err)
 	})
 	t.Run("delete gateway succeeded", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
@@ -685,7 +724,8 @@ func TestController_DeleteDetectorByName(t *testing.T) {
 		stdin.Write([]byte("yes\n"))
 		ctrl := New(&stdin, mockESController, mockADGateway)
 		err := ctrl.DeleteDetectorByName(ctx, mockDetectorName, true, false)
-		assert.NoError(t, err)
+		assert.EqualError(t, err, "failed to delete 1/1 detector(s)")
+		assert.IsType(t, &entity.PartialFailureError{}, err)
 	})
 	t.Run("stop gateway succeeded", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
@@ -26,9 +26,11 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"opensearch-cli/entity"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -36,41 +38,159 @@ import (
 //go:generate go run -mod=mod github.com/golang/mock/mockgen -destination=mocks/mock_config.go -package=mocks . Controller
 type Controller interface {
 	Read() (entity.Config, error)
+	ReadWritable() (entity.Config, error)
 	Write(config entity.Config) error
+	Lock() (unlock func() error, err error)
+	Migrate(dryRun bool) (Migration, error)
 }
 
+const (
+	//FilePermission is used for new config file, existing config file keeps its permission
+	FilePermission = 0600
+	lockFileSuffix = ".lock"
+)
+
 type controller struct {
 	path string
 }
 
-//Read deserialize config file into entity.Config
+//Read deserialize config file into entity.Config, config file created by older opensearch-cli is upgraded
+//to CurrentVersion in memory. Upgraded config is saved by next Write, which is done while holding the lock
 func (c controller) Read() (result entity.Config, err error) {
 	contents, err := ioutil.ReadFile(c.path)
 	if err != nil {
 		return
 	}
-	err = yaml.Unmarshal(contents, &result)
+	migration, err := c.migrate(contents, true)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(migration.Migrated, &result)
 	return
 }
 
-//Write serialize entity.Config into file path
-func (c controller) Write(config entity.Config) (err error) {
-	file, err := os.Create(c.path) //overwrite if file exists
+//ReadWritable deserialize config file which is updated by Write, it is same as Read unless
+//config is overlaid by another config file
+func (c controller) ReadWritable() (entity.Config, error) {
+	return c.Read()
+}
+
+//Write serialize entity.Config into file path with CurrentVersion. If config file was created by older
+//opensearch-cli, it is saved as backup before it is replaced
+func (c controller) Write(config entity.Config) error {
+	config.Version = CurrentVersion
+	contents, err := yaml.Marshal(config)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		err = file.Close()
-	}()
-	contents, err := yaml.Marshal(config)
+	if err = c.backupOlderVersion(); err != nil {
+		return err
+	}
+	return writeFile(c.path, contents)
+}
+
+//backupOlderVersion saves config file as backup if it was created by older opensearch-cli
+func (c controller) backupOlderVersion() error {
+	contents, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	_, err = file.Write(contents)
+	migration, err := upgrade(contents)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade config file %s due to: %w", c.path, err)
+	}
+	if !migration.IsRequired() {
+		return nil
+	}
+	if err = writeFile(backupPath(c.path, migration.FromVersion), contents); err != nil {
+		return fmt.Errorf("failed to backup config file %s due to: %w", c.path, err)
+	}
+	return nil
+}
+
+//Migrate upgrades config file to CurrentVersion, original config file is saved as backup.
+//If dryRun is true, config file is not changed
+func (c controller) Migrate(dryRun bool) (Migration, error) {
+	contents, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return Migration{}, err
+	}
+	return c.migrate(contents, dryRun)
+}
+
+func (c controller) migrate(contents []byte, dryRun bool) (Migration, error) {
+	result, err := upgrade(contents)
+	if err != nil {
+		return result, fmt.Errorf("failed to upgrade config file %s due to: %w", c.path, err)
+	}
+	if dryRun || !result.IsRequired() {
+		return result, nil
+	}
+	backup := backupPath(c.path, result.FromVersion)
+	if err = writeFile(backup, contents); err != nil {
+		return result, fmt.Errorf("failed to backup config file %s due to: %w", c.path, err)
+	}
+	if err = writeFile(c.path, result.Migrated); err != nil {
+		return result, fmt.Errorf("failed to upgrade config file %s due to: %w", c.path, err)
+	}
+	result.BackupPath = backup
+	return result, nil
+}
+
+//writeFile writes contents into a temporary file in same folder, and renames it to path,
+//so that file is either replaced completely or not changed at all. Permission of existing file is preserved
+func writeFile(path string, contents []byte) (err error) {
+	perm := os.FileMode(FilePermission)
+	if info, statErr := os.Stat(path); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	return file.Sync()
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+	if err = file.Chmod(perm); err != nil {
+		return err
+	}
+	if _, err = file.Write(contents); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+//Lock acquires an advisory lock on config file, blocks until lock is available. Lock is held on a separate
+//lock file since config file is replaced on every write. Callers which read, modify and write config
+//should hold the lock, so that concurrent writers don't overwrite each other's changes
+func (c controller) Lock() (func() error, error) {
+	file, err := os.OpenFile(c.path+lockFileSuffix, os.O_CREATE|os.O_RDWR, FilePermission)
+	if err != nil {
+		return nil, err
+	}
+	if err = lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock config file due to: %w", err)
+	}
+	return func() error {
+		if err := unlockFile(file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	}, nil
 }
 
 //New returns config controller instance
@@ -31,6 +31,7 @@ import (
 	"opensearch-cli/entity"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -43,6 +44,7 @@ const testFolderName = "testdata"
 
 func getSampleConfig() entity.Config {
 	return entity.Config{
+		Version: CurrentVersion,
 		Profiles: []entity.Profile{
 			{
 				Name:     "local",
@@ -91,3 +93,107 @@ func TestControllerWrite(t *testing.T) {
 		assert.EqualValues(t, getSampleConfig(), config)
 	})
 }
+
+func TestControllerWriteIsAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+	path := filepath.Join(dir, testFileName)
+	t.Run("new file is private", func(t *testing.T) {
+		assert.NoError(t, New(path).Write(getSampleConfig()))
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.EqualValues(t, FilePermission, info.Mode().Perm())
+	})
+	t.Run("existing permission is preserved", func(t *testing.T) {
+		assert.NoError(t, os.Chmod(path, 0400))
+		assert.NoError(t, New(path).Write(getSampleConfig()))
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0400, info.Mode().Perm())
+	})
+	t.Run("temporary files are not left behind", func(t *testing.T) {
+		files, err := ioutil.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+		assert.EqualValues(t, testFileName, files[0].Name())
+	})
+	t.Run("config is not changed if write fails", func(t *testing.T) {
+		assert.NoError(t, os.Chmod(dir, 0500))
+		defer func() {
+			assert.NoError(t, os.Chmod(dir, 0700))
+		}()
+		if f, err := os.Create(filepath.Join(dir, "probe")); err == nil {
+			_ = f.Close()
+			t.Skip("directory permissions are not enforced for current user")
+		}
+		assert.Error(t, New(path).Write(entity.Config{}))
+		cfg, err := New(path).Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig(), cfg)
+	})
+}
+
+func TestControllerLock(t *testing.T) {
+	t.Run("concurrent writers don't lose changes", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "config")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.RemoveAll(dir))
+		}()
+		path := filepath.Join(dir, testFileName)
+		assert.NoError(t, New(path).Write(entity.Config{}))
+		const writers = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, writers)
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				//every writer uses its own controller, like separate commands do
+				ctrl := New(path)
+				unlock, err := ctrl.Lock()
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer func() {
+					errs <- unlock()
+				}()
+				cfg, err := ctrl.Read()
+				if err != nil {
+					errs <- err
+					return
+				}
+				cfg.Profiles = append(cfg.Profiles, entity.Profile{Name: fmt.Sprintf("profile-%d", i)})
+				if err = ctrl.Write(cfg); err != nil {
+					errs <- err
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		cfg, err := New(path).Read()
+		assert.NoError(t, err)
+		assert.Len(t, cfg.Profiles, writers)
+	})
+	t.Run("lock is released", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "config")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.Remove(f.Name()))
+			assert.NoError(t, os.Remove(f.Name()+lockFileSuffix))
+		}()
+		ctrl := New(f.Name())
+		for i := 0; i < 2; i++ {
+			unlock, err := ctrl.Lock()
+			assert.NoError(t, err)
+			assert.NoError(t, unlock())
+		}
+	})
+}
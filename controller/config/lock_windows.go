@@ -0,0 +1,28 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+//allBytes locks whole file irrespective of its size
+const allBytes = ^uint32(0)
+
+//lockFile acquires exclusive lock on file, blocks until lock is available
+func lockFile(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, allBytes, allBytes, new(windows.Overlapped))
+}
+
+//unlockFile releases lock acquired by lockFile
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, allBytes, allBytes, new(windows.Overlapped))
+}
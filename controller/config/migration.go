@@ -0,0 +1,124 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	//CurrentVersion is the config file version written by this opensearch-cli
+	CurrentVersion = 1
+	versionKey     = "version"
+	backupSuffix   = ".bak"
+)
+
+//migration upgrades config document by one version, document is the top level mapping of config file.
+//Version key is updated after migration succeeds, hence, migration should not update it
+type migration func(document *yaml.Node) error
+
+//migrations are applied in order, migration at index i upgrades config file from version i to version i+1.
+//To change config file structure, increase CurrentVersion and append migration which upgrades existing files
+var migrations = []migration{
+	//version 0 files don't have version key, profiles are unchanged
+	func(document *yaml.Node) error { return nil },
+}
+
+//Migration describes upgrade of config file to CurrentVersion
+type Migration struct {
+	FromVersion int
+	ToVersion   int
+	Original    []byte
+	Migrated    []byte
+	//BackupPath is the path where original config file is saved, empty if config file is not changed
+	BackupPath string
+}
+
+//IsRequired returns true if config file is older than CurrentVersion
+func (m Migration) IsRequired() bool {
+	return m.FromVersion < m.ToVersion
+}
+
+//upgrade applies migrations to config file contents, contents are returned as it is if config file is up to date
+func upgrade(contents []byte) (result Migration, err error) {
+	result = Migration{
+		FromVersion: CurrentVersion,
+		ToVersion:   CurrentVersion,
+		Original:    contents,
+		Migrated:    contents,
+	}
+	var document yaml.Node
+	if err = yaml.Unmarshal(contents, &document); err != nil {
+		return result, err
+	}
+	//empty config file, or not a mapping, there is nothing to upgrade
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return result, nil
+	}
+	root := document.Content[0]
+	if result.FromVersion, err = getVersion(root); err != nil {
+		return result, err
+	}
+	if result.FromVersion > CurrentVersion {
+		return result, fmt.Errorf("config file version %d is not supported, latest supported version is %d. Upgrade opensearch-cli to use this config file", result.FromVersion, CurrentVersion)
+	}
+	if !result.IsRequired() {
+		return result, nil
+	}
+	for version := result.FromVersion; version < CurrentVersion; version++ {
+		if err = migrations[version](root); err != nil {
+			return result, fmt.Errorf("failed to migrate config file from version %d to %d due to: %w", version, version+1, err)
+		}
+		setVersion(root, version+1)
+	}
+	result.Migrated, err = yaml.Marshal(&document)
+	return result, err
+}
+
+//getVersion returns value of version key, 0 if version key doesn't exist
+func getVersion(document *yaml.Node) (int, error) {
+	value := findKey(document, versionKey)
+	if value == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid config file version '%s'", value.Value)
+	}
+	return version, nil
+}
+
+//setVersion updates value of version key, version key is added as first key if it doesn't exist
+func setVersion(document *yaml.Node, version int) {
+	if value := findKey(document, versionKey); value != nil {
+		value.Value = strconv.Itoa(version)
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: versionKey}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	document.Content = append([]*yaml.Node{key, value}, document.Content...)
+}
+
+//findKey returns value node of key in mapping, nil if key doesn't exist
+func findKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+//backupPath returns path where config file of given version is saved before migration
+func backupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d%s", path, version, backupSuffix)
+}
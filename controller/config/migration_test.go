@@ -0,0 +1,138 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//copyTestConfig copies config file from testdata into a temporary folder and returns its path
+func copyTestConfig(t *testing.T, name string) (string, func()) {
+	contents, err := ioutil.ReadFile(filepath.Join(testFolderName, name))
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, testFileName)
+	assert.NoError(t, ioutil.WriteFile(path, contents, FilePermission))
+	return path, func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	t.Run("every version has migration", func(t *testing.T) {
+		assert.Len(t, migrations, CurrentVersion)
+	})
+}
+
+func TestControllerMigrate(t *testing.T) {
+	t.Run("read upgrades old config file in memory", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, "config_v0.yaml")
+		defer cleanup()
+		original, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		cfg, err := New(path).Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig(), cfg)
+
+		contents, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.EqualValues(t, original, contents)
+		_, err = os.Stat(backupPath(path, 0))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("write saves backup of old config file", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, "config_v0.yaml")
+		defer cleanup()
+		original, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		ctrl := New(path)
+		cfg, err := ctrl.Read()
+		assert.NoError(t, err)
+		assert.NoError(t, ctrl.Write(cfg))
+
+		backup, err := ioutil.ReadFile(backupPath(path, 0))
+		assert.NoError(t, err)
+		assert.EqualValues(t, original, backup)
+		info, err := os.Stat(backupPath(path, 0))
+		assert.NoError(t, err)
+		assert.EqualValues(t, FilePermission, info.Mode().Perm())
+
+		migrated, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(migrated), "version: 1\n"))
+	})
+	t.Run("dry run doesn't change config file", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, "config_v0.yaml")
+		defer cleanup()
+		original, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		result, err := New(path).Migrate(true)
+		assert.NoError(t, err)
+		assert.True(t, result.IsRequired())
+		assert.EqualValues(t, 0, result.FromVersion)
+		assert.EqualValues(t, CurrentVersion, result.ToVersion)
+		assert.EqualValues(t, original, result.Original)
+		assert.Contains(t, string(result.Migrated), "version: 1")
+		assert.Empty(t, result.BackupPath)
+
+		contents, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.EqualValues(t, original, contents)
+		_, err = os.Stat(backupPath(path, 0))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("migrate saves backup", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, "config_v0.yaml")
+		defer cleanup()
+		result, err := New(path).Migrate(false)
+		assert.NoError(t, err)
+		assert.EqualValues(t, backupPath(path, 0), result.BackupPath)
+		result, err = New(path).Migrate(false)
+		assert.NoError(t, err)
+		assert.False(t, result.IsRequired())
+	})
+	t.Run("current config file is not changed", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, testFileName)
+		defer cleanup()
+		result, err := New(path).Migrate(false)
+		assert.NoError(t, err)
+		assert.False(t, result.IsRequired())
+		assert.Empty(t, result.BackupPath)
+		assert.EqualValues(t, result.Original, result.Migrated)
+	})
+	t.Run("empty config file", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, testFileName)
+		defer cleanup()
+		assert.NoError(t, ioutil.WriteFile(path, nil, FilePermission))
+		result, err := New(path).Migrate(false)
+		assert.NoError(t, err)
+		assert.False(t, result.IsRequired())
+	})
+	t.Run("newer config file is not supported", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, testFileName)
+		defer cleanup()
+		assert.NoError(t, ioutil.WriteFile(path, []byte("version: 100\nprofiles: []\n"), FilePermission))
+		_, err := New(path).Read()
+		assert.EqualError(t, err, "failed to upgrade config file "+path+" due to: config file version 100 is not supported, latest supported version is 1. Upgrade opensearch-cli to use this config file")
+	})
+	t.Run("invalid version", func(t *testing.T) {
+		path, cleanup := copyTestConfig(t, testFileName)
+		defer cleanup()
+		assert.NoError(t, ioutil.WriteFile(path, []byte("version: latest\n"), FilePermission))
+		_, err := New(path).Read()
+		assert.EqualError(t, err, "failed to upgrade config file "+path+" due to: invalid config file version 'latest'")
+	})
+}
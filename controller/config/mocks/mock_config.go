@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	config "opensearch-cli/controller/config"
 	entity "opensearch-cli/entity"
 	reflect "reflect"
 
@@ -34,6 +35,36 @@ func (m *MockController) EXPECT() *MockControllerMockRecorder {
 	return m.recorder
 }
 
+// Lock mocks base method
+func (m *MockController) Lock() (func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock")
+	ret0, _ := ret[0].(func() error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock
+func (mr *MockControllerMockRecorder) Lock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockController)(nil).Lock))
+}
+
+// Migrate mocks base method
+func (m *MockController) Migrate(arg0 bool) (config.Migration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Migrate", arg0)
+	ret0, _ := ret[0].(config.Migration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Migrate indicates an expected call of Migrate
+func (mr *MockControllerMockRecorder) Migrate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockController)(nil).Migrate), arg0)
+}
+
 // Read mocks base method
 func (m *MockController) Read() (entity.Config, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +80,21 @@ func (mr *MockControllerMockRecorder) Read() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockController)(nil).Read))
 }
 
+// ReadWritable mocks base method
+func (m *MockController) ReadWritable() (entity.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadWritable")
+	ret0, _ := ret[0].(entity.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadWritable indicates an expected call of ReadWritable
+func (mr *MockControllerMockRecorder) ReadWritable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadWritable", reflect.TypeOf((*MockController)(nil).ReadWritable))
+}
+
 // Write mocks base method
 func (m *MockController) Write(arg0 entity.Config) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,119 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"opensearch-cli/entity"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//ErrProjectNotTrusted is returned for project config file which is not trusted, or is changed since it was trusted
+var ErrProjectNotTrusted = errors.New("project config file is not trusted")
+
+//overlayController reads config file overlaid by another config file, like project config file. Changes are
+//written only into config file, overlay is never modified. Overlay is used only if it is trusted, see TrustProject
+type overlayController struct {
+	controller
+	overlayPath string
+	//warnings receives warning about overlay which is skipped, warning is written once per controller
+	warnings io.Writer
+	warnOnce *sync.Once
+}
+
+//NewWithOverlay returns config controller instance where profiles and defaults of overlay file take
+//precedence over config file
+func NewWithOverlay(path string, overlayPath string) Controller {
+	return overlayController{
+		controller:  controller{path: path},
+		overlayPath: overlayPath,
+		warnings:    os.Stderr,
+		warnOnce:    &sync.Once{},
+	}
+}
+
+//Read deserialize config file and overlays profiles and defaults of overlay file on it. Overlay which is not
+//trusted, or is changed since it was trusted, is skipped with a warning, so that config file can still be used
+func (c overlayController) Read() (entity.Config, error) {
+	result, err := c.controller.Read()
+	if err != nil {
+		return result, err
+	}
+	overlay, err := readOverlay(c.path, c.overlayPath)
+	if errors.Is(err, ErrProjectNotTrusted) {
+		c.warnOnce.Do(func() {
+			fmt.Fprintf(c.warnings, "Warning: %v\n", err)
+		})
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if overlay.Defaults != nil {
+		result.Defaults = overlay.Defaults
+	}
+	for _, p := range overlay.Profiles {
+		result.Profiles = replaceProfile(result.Profiles, p)
+	}
+	return result, nil
+}
+
+//ReadWritable deserialize config file without overlay, since Write updates only config file
+func (c overlayController) ReadWritable() (entity.Config, error) {
+	return c.controller.Read()
+}
+
+//readOverlay deserialize overlay file if it is trusted by config file, older files are upgraded in memory,
+//since overlay is never modified
+func readOverlay(configPath string, path string) (result entity.Config, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+	trusted, err := IsProjectTrusted(configPath, path, contents)
+	if err != nil {
+		return result, err
+	}
+	if !trusted {
+		return result, fmt.Errorf("%w, or it is changed since it was trusted, hence, %s is skipped. "+
+			"Review it and run 'opensearch-cli config trust' to use it", ErrProjectNotTrusted, path)
+	}
+	if result, err = Decode(contents); err != nil {
+		return result, fmt.Errorf("failed to read config file %s due to: %w", path, err)
+	}
+	return result, nil
+}
+
+//Decode deserialize contents of config file, like exported profiles, into entity.Config. Older
+//config is upgraded in memory
+func Decode(contents []byte) (result entity.Config, err error) {
+	migration, err := upgrade(contents)
+	if err != nil {
+		return result, err
+	}
+	err = yaml.Unmarshal(migration.Migrated, &result)
+	return result, err
+}
+
+//replaceProfile replaces profile with same name as p, or appends p if it doesn't exist
+func replaceProfile(profiles []entity.Profile, p entity.Profile) []entity.Profile {
+	for i := range profiles {
+		if profiles[i].Name == p.Name {
+			profiles[i] = p
+			return profiles
+		}
+	}
+	return append(profiles, p)
+}
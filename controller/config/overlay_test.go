@@ -0,0 +1,162 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"opensearch-cli/entity"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testOverlay = `# project clusters
+defaults:
+  max_retry: 5
+profiles:
+- name: default
+  extends: local
+  endpoint: https://project:9200
+- name: staging
+  endpoint: https://staging:9200
+`
+
+//newTestOverlayController returns overlay controller which writes warnings into given writer
+func newTestOverlayController(path string, overlayPath string, warnings io.Writer) Controller {
+	ctrl := NewWithOverlay(path, overlayPath).(overlayController)
+	ctrl.warnings = warnings
+	return ctrl
+}
+
+func TestOverlayController(t *testing.T) {
+	path, cleanup := copyTestConfig(t, testFileName)
+	defer cleanup()
+	overlayPath := filepath.Join(filepath.Dir(path), ".opensearch-cli.yaml")
+	assert.NoError(t, ioutil.WriteFile(overlayPath, []byte(testOverlay), 0644))
+	assert.NoError(t, TrustProject(path, overlayPath, []byte(testOverlay)))
+	maxRetry := 5
+	expected := getSampleConfig()
+	expected.Defaults = &entity.Profile{MaxRetry: &maxRetry}
+	expected.Profiles[1] = entity.Profile{Name: "default", Extends: "local", Endpoint: "https://project:9200"}
+	expected.Profiles = append(expected.Profiles, entity.Profile{Name: "staging", Endpoint: "https://staging:9200"})
+
+	t.Run("overlay takes precedence", func(t *testing.T) {
+		actual, err := NewWithOverlay(path, overlayPath).Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, actual)
+	})
+	t.Run("write updates only config file", func(t *testing.T) {
+		ctrl := NewWithOverlay(path, overlayPath)
+		writable, err := ctrl.ReadWritable()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig(), writable)
+		writable.Profiles = writable.Profiles[:1]
+		assert.NoError(t, ctrl.Write(writable))
+
+		contents, err := ioutil.ReadFile(overlayPath)
+		assert.NoError(t, err)
+		assert.EqualValues(t, testOverlay, string(contents))
+		actual, err := ctrl.Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, []entity.Profile{getSampleConfig().Profiles[0], expected.Profiles[1], expected.Profiles[2]}, actual.Profiles)
+	})
+	t.Run("old overlay is not migrated", func(t *testing.T) {
+		_, err := os.Stat(backupPath(overlayPath, 0))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("overlay doesn't exist", func(t *testing.T) {
+		missing := filepath.Join(filepath.Dir(path), "missing.yaml")
+		_, err := NewWithOverlay(path, missing).Read()
+		assert.EqualError(t, err, "open "+missing+": no such file or directory")
+	})
+	t.Run("invalid overlay", func(t *testing.T) {
+		invalid := filepath.Join(filepath.Dir(path), "invalid.yaml")
+		assert.NoError(t, ioutil.WriteFile(invalid, []byte("profiles: {"), 0644))
+		assert.NoError(t, TrustProject(path, invalid, []byte("profiles: {")))
+		_, err := NewWithOverlay(path, invalid).Read()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config file "+invalid+" due to:")
+	})
+	t.Run("overlay which is not trusted", func(t *testing.T) {
+		untrusted := filepath.Join(filepath.Dir(path), "untrusted.yaml")
+		assert.NoError(t, ioutil.WriteFile(untrusted, []byte(testOverlay), 0644))
+		var warnings bytes.Buffer
+		ctrl := newTestOverlayController(path, untrusted, &warnings)
+		actual, err := ctrl.Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig().Profiles[:1], actual.Profiles, "config file is used without overlay")
+		_, err = ctrl.Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "Warning: project config file is not trusted, or it is changed since it was trusted, hence, "+
+			untrusted+" is skipped. Review it and run 'opensearch-cli config trust' to use it\n", warnings.String(),
+			"warning is written once")
+		writable, err := NewWithOverlay(path, untrusted).ReadWritable()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig().Profiles[:1], writable.Profiles)
+	})
+	t.Run("overlay which is changed after it is trusted", func(t *testing.T) {
+		changed := testOverlay + "- name: prod\n  extends: prod-admin\n  endpoint: https://attacker:9200\n"
+		assert.NoError(t, ioutil.WriteFile(overlayPath, []byte(changed), 0644))
+		var warnings bytes.Buffer
+		actual, err := newTestOverlayController(path, overlayPath, &warnings).Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig().Profiles[:1], actual.Profiles)
+		assert.Contains(t, warnings.String(), "is not trusted, or it is changed since it was trusted")
+
+		assert.NoError(t, TrustProject(path, overlayPath, []byte(changed)))
+		actual, err = NewWithOverlay(path, overlayPath).Read()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "https://attacker:9200", actual.Profiles[len(actual.Profiles)-1].Endpoint)
+	})
+}
+
+func TestTrustProject(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	projectPath := filepath.Join(dir, "project", ".opensearch-cli.yaml")
+	trusted, err := IsProjectTrusted(configPath, projectPath, []byte(testOverlay))
+	assert.NoError(t, err)
+	assert.False(t, trusted, "nothing is trusted if trust store doesn't exist")
+
+	assert.NoError(t, TrustProject(configPath, projectPath, []byte(testOverlay)))
+	info, err := os.Stat(filepath.Join(dir, trustFileName))
+	assert.NoError(t, err)
+	assert.EqualValues(t, FilePermission, info.Mode().Perm())
+	trusted, err = IsProjectTrusted(configPath, projectPath, []byte(testOverlay))
+	assert.NoError(t, err)
+	assert.True(t, trusted)
+	trusted, err = IsProjectTrusted(configPath, filepath.Join(dir, ".opensearch-cli.yaml"), []byte(testOverlay))
+	assert.NoError(t, err)
+	assert.False(t, trusted, "same contents at another path are not trusted")
+}
+
+func TestTrustProjectConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, TrustProject(configPath, filepath.Join(dir, strconv.Itoa(i), ".opensearch-cli.yaml"), []byte(testOverlay)))
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		trusted, err := IsProjectTrusted(configPath, filepath.Join(dir, strconv.Itoa(i), ".opensearch-cli.yaml"), []byte(testOverlay))
+		assert.NoError(t, err)
+		assert.True(t, trusted, "trust of concurrent command is not lost")
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"opensearch-cli/environment"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	//encryptedPrefix identifies encrypted values in config file, version allows changing the format later
+	encryptedPrefix = "enc:v1:"
+	saltSize        = 16
+	keySize         = 32
+	kdfIterations   = 100000
+)
+
+//ErrKeyNotFound is returned if secrets need to be encrypted or decrypted, but neither key file nor passphrase is provided
+var ErrKeyNotFound = fmt.Errorf("encryption key is not found, set %s or %s",
+	environment.OPENSEARCH_CLI_KEY_FILE, environment.OPENSEARCH_CLI_PASSPHRASE)
+
+//Cipher encrypts and decrypts secrets stored in config file using AES-GCM. Every value is encrypted with
+//a key derived from key material and a random salt using PBKDF2-HMAC-SHA256
+type Cipher struct {
+	material []byte
+}
+
+//NewCipher returns new Cipher instance, material is either contents of key file or passphrase
+func NewCipher(material []byte) (*Cipher, error) {
+	if len(material) < 1 {
+		return nil, errors.New("key to encrypt secrets cannot be empty")
+	}
+	return &Cipher{material: material}, nil
+}
+
+//CipherFromEnvironment returns Cipher using key file or passphrase from environment variables, key file takes precedence.
+//Returns nil if neither is provided
+func CipherFromEnvironment() (*Cipher, error) {
+	if path, ok := os.LookupEnv(environment.OPENSEARCH_CLI_KEY_FILE); ok && len(path) > 0 {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file due to: %w", err)
+		}
+		return NewCipher(bytes.TrimSpace(contents))
+	}
+	if passphrase, ok := os.LookupEnv(environment.OPENSEARCH_CLI_PASSPHRASE); ok && len(passphrase) > 0 {
+		return NewCipher([]byte(passphrase))
+	}
+	return nil, nil
+}
+
+//IsEncrypted checks whether value is encrypted by Cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+//Encrypt encrypts value, empty and already encrypted values are returned as it is
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if len(value) < 1 || IsEncrypted(value) {
+		return value, nil
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+	gcm, err := c.newGCM(salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nil, nonce, []byte(value), nil)
+	payload := append(append(salt, nonce...), sealed...)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(payload), nil
+}
+
+//Decrypt decrypts value encrypted by Encrypt, values which are not encrypted are returned as it is
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	payload, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(payload) < saltSize {
+		return "", errors.New("encrypted secret is malformed")
+	}
+	gcm, err := c.newGCM(payload[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	payload = payload[saltSize:]
+	if len(payload) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is malformed")
+	}
+	plain, err := gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt secret, key file or passphrase is not the one used to encrypt it")
+	}
+	return string(plain), nil
+}
+
+func (c *Cipher) newGCM(salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key(c.material, salt, kdfIterations, keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,113 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"opensearch-cli/environment"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCipherKeyDerivation(t *testing.T) {
+	t.Run("secrets encrypted by earlier versions are decrypted", func(t *testing.T) {
+		c, err := NewCipher([]byte("passphrase"))
+		assert.NoError(t, err)
+		decrypted, err := c.Decrypt("enc:v1:uVHgmkuFo4hQmIlSjl7tss/l+i5FnkD/LYFtbm6xph1AfCvX9iFI77ouQXOT/3o+Zw==")
+		assert.NoError(t, err)
+		assert.EqualValues(t, "admin", decrypted)
+	})
+}
+
+func TestCipher(t *testing.T) {
+	c, err := NewCipher([]byte("passphrase"))
+	assert.NoError(t, err)
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		encrypted, err := c.Encrypt("admin")
+		assert.NoError(t, err)
+		assert.True(t, IsEncrypted(encrypted))
+		assert.NotContains(t, encrypted, "admin")
+		decrypted, err := c.Decrypt(encrypted)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "admin", decrypted)
+	})
+	t.Run("same value is encrypted differently", func(t *testing.T) {
+		first, err := c.Encrypt("admin")
+		assert.NoError(t, err)
+		second, err := c.Encrypt("admin")
+		assert.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+	t.Run("empty and encrypted values are not encrypted again", func(t *testing.T) {
+		empty, err := c.Encrypt("")
+		assert.NoError(t, err)
+		assert.Empty(t, empty)
+		encrypted, err := c.Encrypt("admin")
+		assert.NoError(t, err)
+		again, err := c.Encrypt(encrypted)
+		assert.NoError(t, err)
+		assert.EqualValues(t, encrypted, again)
+	})
+	t.Run("plain text value is decrypted as it is", func(t *testing.T) {
+		decrypted, err := c.Decrypt("admin")
+		assert.NoError(t, err)
+		assert.EqualValues(t, "admin", decrypted)
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		encrypted, err := c.Encrypt("admin")
+		assert.NoError(t, err)
+		other, err := NewCipher([]byte("other"))
+		assert.NoError(t, err)
+		_, err = other.Decrypt(encrypted)
+		assert.EqualError(t, err, "failed to decrypt secret, key file or passphrase is not the one used to encrypt it")
+	})
+	t.Run("malformed value", func(t *testing.T) {
+		_, err := c.Decrypt(encryptedPrefix + "not-base64!")
+		assert.EqualError(t, err, "encrypted secret is malformed")
+	})
+	t.Run("empty key", func(t *testing.T) {
+		_, err := NewCipher(nil)
+		assert.EqualError(t, err, "key to encrypt secrets cannot be empty")
+	})
+}
+
+func TestCipherFromEnvironment(t *testing.T) {
+	unset := func() {
+		assert.NoError(t, os.Unsetenv(environment.OPENSEARCH_CLI_KEY_FILE))
+		assert.NoError(t, os.Unsetenv(environment.OPENSEARCH_CLI_PASSPHRASE))
+	}
+	t.Run("no key", func(t *testing.T) {
+		unset()
+		c, err := CipherFromEnvironment()
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+	t.Run("key file takes precedence over passphrase", func(t *testing.T) {
+		defer unset()
+		f, err := ioutil.TempFile("", "key")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.Remove(f.Name()))
+		}()
+		assert.NoError(t, ioutil.WriteFile(f.Name(), []byte("key-file-contents\n"), 0600))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_CLI_KEY_FILE, f.Name()))
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_CLI_PASSPHRASE, "passphrase"))
+		c, err := CipherFromEnvironment()
+		assert.NoError(t, err)
+		assert.EqualValues(t, []byte("key-file-contents"), c.material)
+	})
+	t.Run("missing key file", func(t *testing.T) {
+		defer unset()
+		assert.NoError(t, os.Setenv(environment.OPENSEARCH_CLI_KEY_FILE, "testdata/missing.key"))
+		_, err := CipherFromEnvironment()
+		assert.EqualError(t, err, "failed to read key file due to: open testdata/missing.key: no such file or directory")
+	})
+}
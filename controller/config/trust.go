@@ -0,0 +1,98 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+//trustFileName is the file, next to config file, where checksums of trusted project config files are saved
+const trustFileName = "trusted-projects.yaml"
+
+//trustStore maps path of every trusted project config file to checksum of its contents
+type trustStore struct {
+	Projects map[string]string `yaml:"projects"`
+}
+
+//TrustProject trusts given contents of project config file, for config file at configPath. Project file can run
+//commands and send credentials of profiles to its endpoints, hence, it is used only if it is trusted and it is not
+//changed since then. Contents are passed by caller, so that the contents which are reviewed by user are trusted
+func TrustProject(configPath string, projectPath string, contents []byte) (err error) {
+	path, err := filepath.Abs(projectPath)
+	if err != nil {
+		return err
+	}
+	//trust store is updated under config lock, so that concurrent commands don't lose each other's entries
+	unlock, err := controller{path: configPath}.Lock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
+	store, err := readTrustStore(configPath)
+	if err != nil {
+		return err
+	}
+	store.Projects[path] = checksum(contents)
+	data, err := yaml.Marshal(store)
+	if err != nil {
+		return err
+	}
+	return writeFile(trustStorePath(configPath), data)
+}
+
+//IsProjectTrusted checks whether given contents of project config file are trusted, for config file at configPath
+func IsProjectTrusted(configPath string, projectPath string, contents []byte) (bool, error) {
+	path, err := filepath.Abs(projectPath)
+	if err != nil {
+		return false, err
+	}
+	store, err := readTrustStore(configPath)
+	if err != nil {
+		return false, err
+	}
+	return store.Projects[path] == checksum(contents), nil
+}
+
+//trustStorePath returns path of trust store of config file
+func trustStorePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), trustFileName)
+}
+
+//readTrustStore deserialize trust store, store which doesn't exist trusts no project
+func readTrustStore(configPath string) (trustStore, error) {
+	store := trustStore{}
+	contents, err := ioutil.ReadFile(trustStorePath(configPath))
+	if err != nil && !os.IsNotExist(err) {
+		return store, err
+	}
+	if err = yaml.Unmarshal(contents, &store); err != nil {
+		return store, fmt.Errorf("failed to read trusted project config files due to: %w", err)
+	}
+	if store.Projects == nil {
+		store.Projects = map[string]string{}
+	}
+	return store, nil
+}
+
+//checksum returns hex encoded sha256 of contents
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -35,6 +35,21 @@ func (m *MockController) EXPECT() *MockControllerMockRecorder {
 	return m.recorder
 }
 
+// CheckConnection mocks base method
+func (m *MockController) CheckConnection(arg0 context.Context, arg1 string) (platform.ConnectionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckConnection", arg0, arg1)
+	ret0, _ := ret[0].(platform.ConnectionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckConnection indicates an expected call of CheckConnection
+func (mr *MockControllerMockRecorder) CheckConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckConnection", reflect.TypeOf((*MockController)(nil).CheckConnection), arg0, arg1)
+}
+
 // Curl mocks base method
 func (m *MockController) Curl(arg0 context.Context, arg1 platform.CurlCommandRequest) ([]byte, error) {
 	m.ctrl.T.Helper()
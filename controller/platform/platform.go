@@ -26,20 +26,36 @@ package platform
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"net/url"
 	"opensearch-cli/entity/platform"
 	osg "opensearch-cli/gateway/platform"
 	mapper "opensearch-cli/mapper/platform"
+	"sort"
+	"strings"
 
 	"fmt"
 )
 
+//TLS verification outcome reported by CheckConnection
+const (
+	TLSVerified      = "verified"
+	TLSSkipped       = "skipped"
+	TLSFailed        = "failed"
+	TLSNotApplicable = "not applicable"
+	TLSUnknown       = "unknown"
+)
+
 //go:generate go run -mod=mod github.com/golang/mock/mockgen  -destination=mocks/mock_platform.go -package=mocks . Controller
 
 //Controller is an interface for OpenSearch
 type Controller interface {
 	GetDistinctValues(ctx context.Context, index string, field string) ([]interface{}, error)
 	Curl(ctx context.Context, param platform.CurlCommandRequest) ([]byte, error)
+	CheckConnection(ctx context.Context, endpoint string) (platform.ConnectionReport, error)
 }
 
 type controller struct {
@@ -83,3 +99,93 @@ func (c controller) Curl(ctx context.Context, param platform.CurlCommandRequest)
 	}
 	return c.gateway.Curl(ctx, curlRequest)
 }
+
+//CheckConnection checks whether cluster is reachable with given profile and collects details about cluster.
+//Report is always returned, error is returned if cluster cannot be reached or user cannot be authenticated
+func (c controller) CheckConnection(ctx context.Context, endpoint string) (platform.ConnectionReport, error) {
+	report := platform.ConnectionReport{
+		Endpoint: endpoint,
+		TLS:      TLSUnknown,
+	}
+	if u, err := url.Parse(endpoint); err == nil && u.Scheme == "http" {
+		report.TLS = TLSNotApplicable
+	}
+	response, err := c.gateway.GetClusterInfo(ctx)
+	if err != nil {
+		return failedConnection(report, err)
+	}
+	report.Reachable = true
+	if report.TLS == TLSUnknown {
+		report.TLS = TLSVerified
+	}
+	var info platform.ClusterInfo
+	if err = json.Unmarshal(response, &info); err != nil {
+		return failedConnection(report, fmt.Errorf("failed to parse cluster information due to %w", err))
+	}
+	report.ClusterName = info.ClusterName
+	report.Version = info.Version.Number
+	//authinfo is available only if security plugin is installed, hence, ignore failure
+	if response, err = c.gateway.GetAuthInfo(ctx); err == nil {
+		var auth platform.AuthInfo
+		if err = json.Unmarshal(response, &auth); err == nil {
+			report.User = auth.UserName
+		}
+	}
+	if response, err = c.gateway.GetPlugins(ctx); err != nil {
+		return failedConnection(report, err)
+	}
+	var nodePlugins []platform.Plugin
+	if err = json.Unmarshal(response, &nodePlugins); err != nil {
+		return failedConnection(report, fmt.Errorf("failed to parse plugins due to %w", err))
+	}
+	report.Plugins = distinctComponents(nodePlugins)
+	for _, component := range report.Plugins {
+		if strings.HasSuffix(component, "anomaly-detection") {
+			report.AnomalyDetection = true
+		}
+		if strings.HasSuffix(component, "knn") {
+			report.KNN = true
+		}
+	}
+	return report, nil
+}
+
+//failedConnection updates report with error and identifies whether failure is caused by TLS verification
+func failedConnection(report platform.ConnectionReport, err error) (platform.ConnectionReport, error) {
+	report.Error = err.Error()
+	var requestError *platform.RequestError
+	if errors.As(err, &requestError) {
+		report.Reachable = true
+		if report.TLS == TLSUnknown {
+			report.TLS = TLSVerified
+		}
+	}
+	if isTLSError(err) {
+		report.TLS = TLSFailed
+	}
+	return report, err
+}
+
+//isTLSError checks whether error is caused by failure during TLS handshake or certificate verification
+func isTLSError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+	return errors.As(err, &unknownAuthority) || errors.As(err, &hostname) ||
+		errors.As(err, &invalid) || errors.As(err, &recordHeader)
+}
+
+//distinctComponents returns sorted list of plugins installed on any node
+func distinctComponents(nodePlugins []platform.Plugin) []string {
+	components := map[string]struct{}{}
+	for _, p := range nodePlugins {
+		components[p.Component] = struct{}{}
+	}
+	result := make([]string, 0, len(components))
+	for component := range components {
+		result = append(result, component)
+	}
+	sort.Strings(result)
+	return result
+}
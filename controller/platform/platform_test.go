@@ -27,12 +27,16 @@ package platform
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"opensearch-cli/entity/platform"
 	"opensearch-cli/gateway/platform/mocks"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -163,3 +167,86 @@ func TestController_Curl(t *testing.T) {
 		assert.EqualErrorf(t, err, "action cannot be empty", "wrong error message")
 	})
 }
+
+func TestController_CheckConnection(t *testing.T) {
+	endpoint := "https://localhost:9200"
+	t.Run("success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		ctx := context.Background()
+		mockGateway.EXPECT().GetClusterInfo(ctx).Return(helperLoadBytes(t, "cluster_info.json"), nil)
+		mockGateway.EXPECT().GetAuthInfo(ctx).Return([]byte(`{"user_name":"admin","roles":["all_access"]}`), nil)
+		mockGateway.EXPECT().GetPlugins(ctx).Return(helperLoadBytes(t, "plugins.json"), nil)
+		ctrl := New(mockGateway)
+		report, err := ctrl.CheckConnection(ctx, endpoint)
+		assert.NoError(t, err)
+		assert.EqualValues(t, platform.ConnectionReport{
+			Endpoint:         endpoint,
+			Reachable:        true,
+			TLS:              TLSVerified,
+			User:             "admin",
+			ClusterName:      "opensearch-cluster",
+			Version:          "1.0.0",
+			AnomalyDetection: true,
+			KNN:              true,
+			Plugins:          []string{"opensearch-anomaly-detection", "opensearch-knn"},
+		}, report)
+	})
+	t.Run("security plugin is not installed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		ctx := context.Background()
+		mockGateway.EXPECT().GetClusterInfo(ctx).Return(helperLoadBytes(t, "cluster_info.json"), nil)
+		mockGateway.EXPECT().GetAuthInfo(ctx).Return(nil, platform.NewRequestError(http.StatusNotFound, endpoint, ioutil.NopCloser(strings.NewReader("")), errors.New("404 Client Error")))
+		mockGateway.EXPECT().GetPlugins(ctx).Return([]byte("[]"), nil)
+		ctrl := New(mockGateway)
+		report, err := ctrl.CheckConnection(ctx, "http://localhost:9200")
+		assert.NoError(t, err)
+		assert.True(t, report.Reachable)
+		assert.EqualValues(t, TLSNotApplicable, report.TLS)
+		assert.Empty(t, report.User)
+		assert.False(t, report.AnomalyDetection)
+		assert.False(t, report.KNN)
+	})
+	t.Run("authentication failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		ctx := context.Background()
+		authErr := platform.NewRequestError(http.StatusUnauthorized, endpoint, ioutil.NopCloser(strings.NewReader("Unauthorized")), errors.New("401 Client Error"))
+		mockGateway.EXPECT().GetClusterInfo(ctx).Return(nil, authErr)
+		ctrl := New(mockGateway)
+		report, err := ctrl.CheckConnection(ctx, endpoint)
+		assert.EqualError(t, err, "401 Client Error")
+		assert.True(t, report.Reachable)
+		assert.EqualValues(t, TLSVerified, report.TLS)
+		assert.EqualValues(t, "401 Client Error", report.Error)
+	})
+	t.Run("certificate verification failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		ctx := context.Background()
+		tlsErr := &url.Error{Op: "Get", URL: endpoint, Err: x509.UnknownAuthorityError{}}
+		mockGateway.EXPECT().GetClusterInfo(ctx).Return(nil, tlsErr)
+		ctrl := New(mockGateway)
+		report, err := ctrl.CheckConnection(ctx, endpoint)
+		assert.Error(t, err)
+		assert.False(t, report.Reachable)
+		assert.EqualValues(t, TLSFailed, report.TLS)
+	})
+	t.Run("cluster is unreachable", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockGateway := mocks.NewMockGateway(mockCtrl)
+		ctx := context.Background()
+		mockGateway.EXPECT().GetClusterInfo(ctx).Return(nil, fmt.Errorf("dial tcp: connection refused"))
+		ctrl := New(mockGateway)
+		report, err := ctrl.CheckConnection(ctx, endpoint)
+		assert.EqualError(t, err, "dial tcp: connection refused")
+		assert.False(t, report.Reachable)
+		assert.EqualValues(t, TLSUnknown, report.TLS)
+	})
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	profile "opensearch-cli/controller/profile"
 	entity "opensearch-cli/entity"
 	reflect "reflect"
 
@@ -62,6 +63,36 @@ func (mr *MockControllerMockRecorder) DeleteProfiles(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfiles", reflect.TypeOf((*MockController)(nil).DeleteProfiles), arg0)
 }
 
+// EncryptSecrets mocks base method
+func (m *MockController) EncryptSecrets() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptSecrets")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptSecrets indicates an expected call of EncryptSecrets
+func (mr *MockControllerMockRecorder) EncryptSecrets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecrets", reflect.TypeOf((*MockController)(nil).EncryptSecrets))
+}
+
+// ExportProfiles mocks base method
+func (m *MockController) ExportProfiles(arg0 []string, arg1 profile.SecretPolicy) ([]entity.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportProfiles", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportProfiles indicates an expected call of ExportProfiles
+func (mr *MockControllerMockRecorder) ExportProfiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportProfiles", reflect.TypeOf((*MockController)(nil).ExportProfiles), arg0, arg1)
+}
+
 // GetProfileForExecution mocks base method
 func (m *MockController) GetProfileForExecution(arg0 string) (entity.Profile, bool, error) {
 	m.ctrl.T.Helper()
@@ -122,3 +153,76 @@ func (mr *MockControllerMockRecorder) GetProfilesMap() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfilesMap", reflect.TypeOf((*MockController)(nil).GetProfilesMap))
 }
+
+// GetWritableProfile mocks base method
+func (m *MockController) GetWritableProfile(arg0 string) (entity.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWritableProfile", arg0)
+	ret0, _ := ret[0].(entity.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWritableProfile indicates an expected call of GetWritableProfile
+func (mr *MockControllerMockRecorder) GetWritableProfile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWritableProfile", reflect.TypeOf((*MockController)(nil).GetWritableProfile), arg0)
+}
+
+// ImportProfiles mocks base method
+func (m *MockController) ImportProfiles(arg0 []entity.Profile, arg1 profile.ConflictPolicy) (profile.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportProfiles", arg0, arg1)
+	ret0, _ := ret[0].(profile.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportProfiles indicates an expected call of ImportProfiles
+func (mr *MockControllerMockRecorder) ImportProfiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportProfiles", reflect.TypeOf((*MockController)(nil).ImportProfiles), arg0, arg1)
+}
+
+// RenameProfile mocks base method
+func (m *MockController) RenameProfile(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameProfile", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameProfile indicates an expected call of RenameProfile
+func (mr *MockControllerMockRecorder) RenameProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameProfile", reflect.TypeOf((*MockController)(nil).RenameProfile), arg0, arg1)
+}
+
+// ResolveProfile mocks base method
+func (m *MockController) ResolveProfile(arg0 string) (entity.ResolvedProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveProfile", arg0)
+	ret0, _ := ret[0].(entity.ResolvedProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveProfile indicates an expected call of ResolveProfile
+func (mr *MockControllerMockRecorder) ResolveProfile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveProfile", reflect.TypeOf((*MockController)(nil).ResolveProfile), arg0)
+}
+
+// UpdateProfile mocks base method
+func (m *MockController) UpdateProfile(arg0 entity.Profile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile
+func (mr *MockControllerMockRecorder) UpdateProfile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockController)(nil).UpdateProfile), arg0)
+}
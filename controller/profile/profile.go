@@ -26,6 +26,7 @@
 package profile
 
 import (
+	"errors"
 	"fmt"
 	"opensearch-cli/controller/config"
 	"opensearch-cli/entity"
@@ -38,24 +39,42 @@ const (
 	DefaultProfileName = "default"
 )
 
+//errNotModified is returned by config update which doesn't need to save config
+var errNotModified = errors.New("config is not modified")
+
 //go:generate go run -mod=mod github.com/golang/mock/mockgen -destination=mocks/mock_profile.go -package=mocks . Controller
 type Controller interface {
 	CreateProfile(profile entity.Profile) error
+	UpdateProfile(profile entity.Profile) error
+	RenameProfile(name string, newName string) error
 	DeleteProfiles(names []string) error
 	GetProfiles() ([]entity.Profile, error)
 	GetProfileNames() ([]string, error)
 	GetProfilesMap() (map[string]entity.Profile, error)
+	GetWritableProfile(name string) (entity.Profile, error)
 	GetProfileForExecution(name string) (entity.Profile, bool, error)
+	ResolveProfile(name string) (entity.ResolvedProfile, error)
+	EncryptSecrets() (int, error)
+	ExportProfiles(names []string, policy SecretPolicy) ([]entity.Profile, error)
+	ImportProfiles(profiles []entity.Profile, policy ConflictPolicy) (ImportResult, error)
 }
 
 type controller struct {
 	configCtrl config.Controller
+	cipher     *config.Cipher
 }
 
 //New returns new config controller instance
 func New(c config.Controller) Controller {
+	return NewWithCipher(c, nil)
+}
+
+//NewWithCipher returns new config controller instance which encrypts secrets of new and updated profiles
+//using cipher, and decrypts secrets of profile used for execution. If cipher is nil, secrets are saved as it is
+func NewWithCipher(c config.Controller, cipher *config.Cipher) Controller {
 	return &controller{
 		configCtrl: c,
+		cipher:     cipher,
 	}
 }
 
@@ -94,47 +113,141 @@ func (c controller) GetProfilesMap() (map[string]entity.Profile, error) {
 	return result, nil
 }
 
+//GetWritableProfile returns profile as it is saved in config file, profiles and defaults of project config file
+//are not overlaid, since UpdateProfile saves profile only in config file
+func (c controller) GetWritableProfile(name string) (entity.Profile, error) {
+	data, err := c.configCtrl.ReadWritable()
+	if err != nil {
+		return entity.Profile{}, err
+	}
+	index := findProfile(data.Profiles, name)
+	if index < 0 {
+		return entity.Profile{}, fmt.Errorf("profile '%s' does not exist", name)
+	}
+	return data.Profiles[index], nil
+}
+
 //CreateProfile creates profile by gets list of existing profiles, append new profile to list
 //and saves it in config file
 func (c controller) CreateProfile(p entity.Profile) error {
-	data, err := c.configCtrl.Read()
-	if err != nil {
+	if err := c.encryptSecrets(&p); err != nil {
 		return err
 	}
-	data.Profiles = append(data.Profiles, p)
-	return c.configCtrl.Write(data)
+	return c.updateConfig(func(data *entity.Config) error {
+		//checked while holding config lock, since concurrent command may have created profile with same name
+		if findProfile(data.Profiles, p.Name) >= 0 {
+			return fmt.Errorf("profile '%s' already exists", p.Name)
+		}
+		data.Profiles = append(data.Profiles, p)
+		return nil
+	})
 }
 
-//DeleteProfiles loads all profile, deletes selected profiles, and saves rest in config file
-func (c controller) DeleteProfiles(names []string) error {
-	profilesMap, err := c.GetProfilesMap()
+//UpdateProfile replaces existing profile which has same name as given profile and saves it in config file
+func (c controller) UpdateProfile(p entity.Profile) error {
+	return c.updateConfig(func(data *entity.Config) error {
+		index := findProfile(data.Profiles, p.Name)
+		if index < 0 {
+			return fmt.Errorf("profile '%s' does not exist", p.Name)
+		}
+		if err := c.encryptSecrets(&p); err != nil {
+			return err
+		}
+		data.Profiles[index] = p
+		return nil
+	})
+}
+
+//RenameProfile changes name of existing profile, new name should not be used by any other profile
+func (c controller) RenameProfile(name string, newName string) error {
+	if len(newName) < 1 {
+		return fmt.Errorf("new profile name cannot be empty")
+	}
+	return c.updateConfig(func(data *entity.Config) error {
+		index := findProfile(data.Profiles, name)
+		if index < 0 {
+			return fmt.Errorf("profile '%s' does not exist", name)
+		}
+		if findProfile(data.Profiles, newName) >= 0 {
+			return fmt.Errorf("profile '%s' already exists", newName)
+		}
+		data.Profiles[index].Name = newName
+		for i := range data.Profiles {
+			if data.Profiles[i].Extends == name {
+				data.Profiles[i].Extends = newName
+			}
+		}
+		return nil
+	})
+}
+
+//updateConfig reads writable config, applies update and saves config while holding config lock, so that
+//changes made by concurrent commands are not lost. Config is not saved if update fails
+func (c controller) updateConfig(update func(data *entity.Config) error) (err error) {
+	unlock, err := c.configCtrl.Lock()
 	if err != nil {
 		return err
 	}
-	var invalidProfileNames []string
-	for _, name := range names {
-		if _, ok := profilesMap[name]; !ok {
-			invalidProfileNames = append(invalidProfileNames, name)
-			continue
+	defer func() {
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
 		}
-		delete(profilesMap, name)
-	}
-
-	//load config
-	data, err := c.configCtrl.Read()
+	}()
+	data, err := c.configCtrl.ReadWritable()
 	if err != nil {
 		return err
 	}
+	if err = update(&data); err != nil {
+		if err == errNotModified {
+			return nil
+		}
+		return err
+	}
+	return c.configCtrl.Write(data)
+}
 
-	//empty existing profile
-	data.Profiles = nil
-	for _, p := range profilesMap {
-		// add existing profiles to the list
-		data.Profiles = append(data.Profiles, p)
+//findProfile returns index of profile by name, -1 if profile doesn't exist
+func findProfile(profiles []entity.Profile, name string) int {
+	for i, p := range profiles {
+		if p.Name == name {
+			return i
+		}
 	}
+	return -1
+}
 
-	//save config
-	err = c.configCtrl.Write(data)
+//DeleteProfiles loads all profile, deletes selected profiles, and saves rest in config file
+func (c controller) DeleteProfiles(names []string) error {
+	var invalidProfileNames []string
+	err := c.updateConfig(func(data *entity.Config) error {
+		toDelete := make(map[string]bool)
+		for _, name := range names {
+			if findProfile(data.Profiles, name) < 0 {
+				invalidProfileNames = append(invalidProfileNames, name)
+				continue
+			}
+			toDelete[name] = true
+		}
+		//profiles which extend deleted profile cannot be resolved, unless they are deleted too
+		var dependents []string
+		for _, p := range data.Profiles {
+			if len(p.Extends) > 0 && toDelete[p.Extends] && !toDelete[p.Name] {
+				dependents = append(dependents, fmt.Sprintf("%s extends %s", p.Name, p.Extends))
+			}
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf("profiles cannot be deleted, since other profiles extend them: %s", strings.Join(dependents, ", "))
+		}
+		var profiles []entity.Profile
+		for _, p := range data.Profiles {
+			// add existing profiles to the list
+			if !toDelete[p.Name] {
+				profiles = append(profiles, p)
+			}
+		}
+		data.Profiles = profiles
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -150,24 +263,135 @@ func (c controller) DeleteProfiles(names []string) error {
 // if profile name is provided as an argument, will return the profile,
 // if profile name is not provided as argument, we will check for environment variable
 // in session, then will check for profile named `default`
-// bool determines whether profile is valid or not
+// profile is merged with profiles it extends and defaults of config file
+// bool determines whether profile is found or not, if secrets of found profile cannot be decrypted,
+// empty profile is returned along with error
 func (c controller) GetProfileForExecution(name string) (value entity.Profile, ok bool, err error) {
-	profiles, err := c.GetProfilesMap()
+	data, err := c.configCtrl.Read()
 	if err != nil {
 		return
 	}
+	name, ok, err = findProfileForExecution(data.Profiles, name)
+	if err != nil || !ok {
+		return
+	}
+	resolved, err := resolve(data, name)
+	if err != nil {
+		return value, false, err
+	}
+	value = resolved.Profile
+	if err = c.decryptSecrets(&value); err != nil {
+		//profile exists, but it cannot be used without its secrets
+		return entity.Profile{}, true, err
+	}
+	return
+}
+
+//findProfileForExecution finds profile name by name, else, by environment variable, else, default profile
+func findProfileForExecution(profiles []entity.Profile, name string) (string, bool, error) {
 	if name != "" {
-		if value, ok = profiles[name]; ok {
-			return
+		if findProfile(profiles, name) >= 0 {
+			return name, true, nil
 		}
-		return value, ok, fmt.Errorf("profile '%s' does not exist", name)
+		return name, false, fmt.Errorf("profile '%s' does not exist", name)
 	}
 	if envProfileName, exists := os.LookupEnv(environment.OPENSEARCH_PROFILE); exists {
-		if value, ok = profiles[envProfileName]; ok {
-			return
+		if findProfile(profiles, envProfileName) >= 0 {
+			return envProfileName, true, nil
 		}
-		return value, ok, fmt.Errorf("profile '%s' does not exist", envProfileName)
+		return envProfileName, false, fmt.Errorf("profile '%s' does not exist", envProfileName)
 	}
-	value, ok = profiles[DefaultProfileName]
-	return
+	return DefaultProfileName, findProfile(profiles, DefaultProfileName) >= 0, nil
+}
+
+//ResolveProfile returns profile merged with profiles it extends and defaults of config file, along with
+//source of every setting. Secrets are not decrypted
+func (c controller) ResolveProfile(name string) (entity.ResolvedProfile, error) {
+	data, err := c.configCtrl.Read()
+	if err != nil {
+		return entity.ResolvedProfile{}, err
+	}
+	return resolve(data, name)
+}
+
+//EncryptSecrets encrypts secrets of every profile which are saved as plain text, returns number of updated profiles
+func (c controller) EncryptSecrets() (int, error) {
+	if c.cipher == nil {
+		return 0, config.ErrKeyNotFound
+	}
+	updated := 0
+	err := c.updateConfig(func(data *entity.Config) error {
+		for i := range data.Profiles {
+			if !hasPlainTextSecrets(data.Profiles[i]) {
+				continue
+			}
+			if err := c.encryptSecrets(&data.Profiles[i]); err != nil {
+				return err
+			}
+			updated++
+		}
+		if updated == 0 {
+			return errNotModified
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+//secrets returns pointers to secret fields of profile, nested values are copied, so that
+//updating secrets doesn't modify values shared with other profiles
+func secrets(p *entity.Profile) []*string {
+	fields := []*string{&p.Password, &p.APIKey}
+	if p.Token != nil {
+		token := *p.Token
+		p.Token = &token
+		fields = append(fields, &token.Value)
+	}
+	if p.Proxy != nil {
+		proxy := *p.Proxy
+		p.Proxy = &proxy
+		fields = append(fields, &proxy.Password)
+	}
+	return fields
+}
+
+func hasPlainTextSecrets(p entity.Profile) bool {
+	for _, secret := range secrets(&p) {
+		if len(*secret) > 0 && !config.IsEncrypted(*secret) {
+			return true
+		}
+	}
+	return false
+}
+
+//encryptSecrets encrypts secrets of profile if cipher is available
+func (c controller) encryptSecrets(p *entity.Profile) (err error) {
+	if c.cipher == nil {
+		return nil
+	}
+	for _, secret := range secrets(p) {
+		if *secret, err = c.cipher.Encrypt(*secret); err != nil {
+			return fmt.Errorf("failed to encrypt secrets of profile '%s' due to: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+//decryptSecrets decrypts secrets of profile, fails if secrets are encrypted and cipher is not available
+func (c controller) decryptSecrets(p *entity.Profile) (err error) {
+	for _, secret := range secrets(p) {
+		if !config.IsEncrypted(*secret) {
+			continue
+		}
+		if c.cipher == nil {
+			return fmt.Errorf("failed to decrypt secrets of profile '%s' due to: %w", p.Name, config.ErrKeyNotFound)
+		}
+		if *secret, err = c.cipher.Decrypt(*secret); err != nil {
+			return fmt.Errorf("failed to decrypt secrets of profile '%s' due to: %w", p.Name, err)
+		}
+	}
+	return nil
 }
@@ -27,6 +27,7 @@ package profile
 
 import (
 	"errors"
+	cfg "opensearch-cli/controller/config"
 	config "opensearch-cli/controller/config/mocks"
 	"opensearch-cli/entity"
 	"opensearch-cli/environment"
@@ -91,6 +92,28 @@ func TestControllerGetProfilesMap(t *testing.T) {
 	})
 }
 
+func TestControllerGetWritableProfile(t *testing.T) {
+	t.Run("profile is read from writable config", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		actual, err := ctrl.GetWritableProfile("default")
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig().Profiles[1], actual)
+	})
+	t.Run("profile doesn't exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		_, err := ctrl.GetWritableProfile("project")
+		assert.EqualError(t, err, "profile 'project' does not exist")
+	})
+}
+
 func TestControllerGetProfiles(t *testing.T) {
 
 	profiles := entity.Config{
@@ -252,12 +275,19 @@ func TestControllerGetProfileForExecution(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+//expectLock expects config lock to be acquired once
+func expectLock(mockConfigCtrl *config.MockController) {
+	mockConfigCtrl.EXPECT().Lock().Return(func() error { return nil }, nil)
+}
+
 func TestControllerCreateProfile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(entity.Config{}, nil)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, nil)
 		mockConfigCtrl.EXPECT().Write(getDefaultConfig()).Return(nil)
 		ctrl := New(mockConfigCtrl)
 		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
@@ -267,16 +297,48 @@ func TestControllerCreateProfile(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(entity.Config{}, errors.New("failed to read"))
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, errors.New("failed to read"))
 		ctrl := New(mockConfigCtrl)
 		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
 		assert.EqualError(t, err, "failed to read")
 	})
+	t.Run("profile already exists", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getDefaultConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
+		assert.EqualError(t, err, "profile '"+getDefaultConfig().Profiles[0].Name+"' already exists")
+	})
+	t.Run("config lock failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		mockConfigCtrl.EXPECT().Lock().Return(nil, errors.New("failed to lock"))
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
+		assert.EqualError(t, err, "failed to lock")
+	})
+	t.Run("config unlock failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		mockConfigCtrl.EXPECT().Lock().Return(func() error { return errors.New("failed to unlock") }, nil)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, nil)
+		mockConfigCtrl.EXPECT().Write(getDefaultConfig()).Return(nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
+		assert.EqualError(t, err, "failed to unlock")
+	})
 	t.Run("config controller write failed", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(entity.Config{}, nil)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, nil)
 		mockConfigCtrl.EXPECT().Write(getDefaultConfig()).Return(errors.New("failed to write"))
 		ctrl := New(mockConfigCtrl)
 		err := ctrl.CreateProfile(getDefaultConfig().Profiles[0])
@@ -289,7 +351,8 @@ func TestControllerDeleteProfile(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(getSampleConfig(), nil).Times(2)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
 		expectedConfig := getSampleConfig()
 		expectedConfig.Profiles = []entity.Profile{expectedConfig.Profiles[1]}
 		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(nil)
@@ -301,7 +364,8 @@ func TestControllerDeleteProfile(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(getSampleConfig(), nil).Times(2)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
 		expectedConfig := getSampleConfig()
 		expectedConfig.Profiles = []entity.Profile{expectedConfig.Profiles[1]}
 		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(nil)
@@ -309,11 +373,34 @@ func TestControllerDeleteProfile(t *testing.T) {
 		err := ctrl.DeleteProfiles([]string{getSampleConfig().Profiles[0].Name, "invalid-profile1", "invalid-profile2"})
 		assert.EqualError(t, err, "no profiles found for: invalid-profile1, invalid-profile2")
 	})
+	t.Run("profile which is extended", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		withDependents := getSampleConfig()
+		withDependents.Profiles = append(withDependents.Profiles,
+			entity.Profile{Name: "local-eu", Extends: "local"},
+			entity.Profile{Name: "local-us", Extends: "local"})
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(withDependents, nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.DeleteProfiles([]string{"local", "local-us"})
+		assert.EqualError(t, err, "profiles cannot be deleted, since other profiles extend them: local-eu extends local")
+
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(withDependents, nil)
+		expectedConfig := getSampleConfig()
+		expectedConfig.Profiles = []entity.Profile{expectedConfig.Profiles[1]}
+		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(nil)
+		assert.NoError(t, ctrl.DeleteProfiles([]string{"local", "local-us", "local-eu"}),
+			"profiles can be deleted along with profiles which extend them")
+	})
 	t.Run("config controller read failed", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(entity.Config{}, errors.New("failed to read"))
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, errors.New("failed to read"))
 		ctrl := New(mockConfigCtrl)
 		err := ctrl.DeleteProfiles([]string{getSampleConfig().Profiles[0].Name})
 		assert.EqualError(t, err, "failed to read")
@@ -322,7 +409,8 @@ func TestControllerDeleteProfile(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockConfigCtrl := config.NewMockController(mockCtrl)
-		mockConfigCtrl.EXPECT().Read().Return(getSampleConfig(), nil).Times(2)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
 		expectedConfig := getSampleConfig()
 		expectedConfig.Profiles = []entity.Profile{expectedConfig.Profiles[1]}
 		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(errors.New("failed to write"))
@@ -331,3 +419,195 @@ func TestControllerDeleteProfile(t *testing.T) {
 		assert.EqualError(t, err, "failed to write")
 	})
 }
+
+func TestControllerUpdateProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		updatedProfile := getSampleConfig().Profiles[0]
+		updatedProfile.Endpoint = "https://127.0.0.2:9200"
+		updatedProfile.Password = "new-password"
+		expectedConfig := getSampleConfig()
+		expectedConfig.Profiles[0] = updatedProfile
+		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.UpdateProfile(updatedProfile)
+		assert.NoError(t, err)
+	})
+	t.Run("profile doesn't exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.UpdateProfile(entity.Profile{Name: "invalid"})
+		assert.EqualError(t, err, "profile 'invalid' does not exist")
+	})
+	t.Run("config controller read failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, errors.New("failed to read"))
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.UpdateProfile(getSampleConfig().Profiles[0])
+		assert.EqualError(t, err, "failed to read")
+	})
+	t.Run("config controller write failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		mockConfigCtrl.EXPECT().Write(getSampleConfig()).Return(errors.New("failed to write"))
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.UpdateProfile(getSampleConfig().Profiles[0])
+		assert.EqualError(t, err, "failed to write")
+	})
+}
+
+func TestControllerRenameProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		expectedConfig := getSampleConfig()
+		expectedConfig.Profiles[0].Name = "dev"
+		mockConfigCtrl.EXPECT().Write(expectedConfig).Return(nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.RenameProfile("local", "dev")
+		assert.NoError(t, err)
+	})
+	t.Run("profile doesn't exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.RenameProfile("invalid", "dev")
+		assert.EqualError(t, err, "profile 'invalid' does not exist")
+	})
+	t.Run("new name already exists", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getSampleConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.RenameProfile("local", DefaultProfileName)
+		assert.EqualError(t, err, "profile 'default' already exists")
+	})
+	t.Run("empty new name", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		ctrl := New(mockConfigCtrl)
+		err := ctrl.RenameProfile("local", "")
+		assert.EqualError(t, err, "new profile name cannot be empty")
+	})
+}
+
+func TestControllerEncryptedSecrets(t *testing.T) {
+	cipher, err := cfg.NewCipher([]byte("passphrase"))
+	assert.NoError(t, err)
+	encrypt := func(value string) string {
+		encrypted, err := cipher.Encrypt(value)
+		assert.NoError(t, err)
+		return encrypted
+	}
+	t.Run("create profile encrypts secrets", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{}, nil)
+		newProfile := entity.Profile{
+			Name:     "token",
+			Endpoint: "https://localhost:9200",
+			Token:    &entity.Token{Value: "eyJhbGciOi"},
+			Proxy:    &entity.Proxy{URL: "http://proxy:3128", UserName: "proxy", Password: "proxy-password"},
+		}
+		var saved entity.Config
+		mockConfigCtrl.EXPECT().Write(gomock.Any()).DoAndReturn(func(c entity.Config) error {
+			saved = c
+			return nil
+		})
+		ctrl := NewWithCipher(mockConfigCtrl, cipher)
+		assert.NoError(t, ctrl.CreateProfile(newProfile))
+		assert.True(t, cfg.IsEncrypted(saved.Profiles[0].Token.Value))
+		assert.True(t, cfg.IsEncrypted(saved.Profiles[0].Proxy.Password))
+		assert.EqualValues(t, "proxy", saved.Profiles[0].Proxy.UserName)
+		assert.EqualValues(t, "eyJhbGciOi", newProfile.Token.Value)
+	})
+	t.Run("profile for execution is decrypted", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		encryptedConfig := getSampleConfig()
+		encryptedConfig.Profiles[0].Password = encrypt("admin")
+		mockConfigCtrl.EXPECT().Read().Return(encryptedConfig, nil)
+		ctrl := NewWithCipher(mockConfigCtrl, cipher)
+		p, ok, err := ctrl.GetProfileForExecution("local")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.EqualValues(t, getSampleConfig().Profiles[0], p)
+	})
+	t.Run("encrypted profile without key", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		encryptedConfig := getSampleConfig()
+		encryptedConfig.Profiles[0].Password = encrypt("admin")
+		mockConfigCtrl.EXPECT().Read().Return(encryptedConfig, nil)
+		ctrl := New(mockConfigCtrl)
+		p, ok, err := ctrl.GetProfileForExecution("local")
+		assert.EqualError(t, err, "failed to decrypt secrets of profile 'local' due to: encryption key is not found, set OPENSEARCH_CLI_KEY_FILE or OPENSEARCH_CLI_PASSPHRASE")
+		assert.True(t, ok)
+		assert.EqualValues(t, entity.Profile{}, p, "encrypted secrets should not be returned")
+	})
+	t.Run("encrypt secrets of existing profiles", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		partiallyEncrypted := getSampleConfig()
+		partiallyEncrypted.Profiles[0].Password = encrypt("admin")
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(partiallyEncrypted, nil)
+		var saved entity.Config
+		mockConfigCtrl.EXPECT().Write(gomock.Any()).DoAndReturn(func(c entity.Config) error {
+			saved = c
+			return nil
+		})
+		ctrl := NewWithCipher(mockConfigCtrl, cipher)
+		updated, err := ctrl.EncryptSecrets()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+		assert.EqualValues(t, partiallyEncrypted.Profiles[0].Password, saved.Profiles[0].Password)
+		assert.True(t, cfg.IsEncrypted(saved.Profiles[1].Password))
+	})
+	t.Run("nothing to encrypt", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(entity.Config{Profiles: []entity.Profile{{Name: "disabled"}}}, nil)
+		ctrl := NewWithCipher(mockConfigCtrl, cipher)
+		updated, err := ctrl.EncryptSecrets()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, updated)
+	})
+	t.Run("encrypt secrets without key", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ctrl := New(config.NewMockController(mockCtrl))
+		_, err := ctrl.EncryptSecrets()
+		assert.EqualError(t, err, "encryption key is not found, set OPENSEARCH_CLI_KEY_FILE or OPENSEARCH_CLI_PASSPHRASE")
+	})
+}
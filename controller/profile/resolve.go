@@ -0,0 +1,121 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package profile
+
+import (
+	"fmt"
+	"opensearch-cli/entity"
+	"reflect"
+	"strings"
+)
+
+//notInherited are settings of a profile which are never inherited from other profiles or defaults
+var notInherited = map[string]bool{
+	"name":    true,
+	"extends": true,
+}
+
+//credentials are settings which authenticate a profile, they are inherited as a group, once a profile sets any of
+//them, none of them is inherited, so that a profile with token doesn't send user and password of extended profile
+var credentials = map[string]bool{
+	"user":               true,
+	"password":           true,
+	"token":              true,
+	"api_key":            true,
+	"credential_process": true,
+	"aws_iam":            true,
+}
+
+//resolve merges profile with profiles it extends, nearest profile first, and then with defaults of config.
+//A setting is inherited only if it is not set by profile
+func resolve(data entity.Config, name string) (entity.ResolvedProfile, error) {
+	index := findProfile(data.Profiles, name)
+	if index < 0 {
+		return entity.ResolvedProfile{}, fmt.Errorf("profile '%s' does not exist", name)
+	}
+	result := entity.ResolvedProfile{
+		Sources: map[string]string{},
+	}
+	visited := map[string]bool{}
+	for p := data.Profiles[index]; ; {
+		visited[p.Name] = true
+		merge(reflect.ValueOf(&result.Profile).Elem(), reflect.ValueOf(p), "", p.Name, result.Sources)
+		if len(p.Extends) == 0 {
+			break
+		}
+		if visited[p.Extends] {
+			return entity.ResolvedProfile{}, fmt.Errorf("profile '%s' cannot be resolved, profile '%s' extends '%s' which forms a cycle", name, p.Name, p.Extends)
+		}
+		parent := findProfile(data.Profiles, p.Extends)
+		if parent < 0 {
+			return entity.ResolvedProfile{}, fmt.Errorf("profile '%s' extends '%s' which does not exist", p.Name, p.Extends)
+		}
+		p = data.Profiles[parent]
+	}
+	if data.Defaults != nil {
+		merge(reflect.ValueOf(&result.Profile).Elem(), reflect.ValueOf(*data.Defaults), "", "", result.Sources)
+	}
+	own := data.Profiles[index]
+	result.Name = own.Name
+	result.Extends = own.Extends
+	return result, nil
+}
+
+//merge sets fields of dst which are not set from src, and records source of every field it sets.
+//Nested settings, like certificate, are merged field by field, credentials are merged only if dst has none of them
+func merge(dst reflect.Value, src reflect.Value, prefix string, source string, sources map[string]string) {
+	hasCredentials := len(prefix) == 0 && hasAny(dst, credentials)
+	for i := 0; i < dst.NumField(); i++ {
+		key := prefix + yamlKey(dst.Type().Field(i))
+		if notInherited[key] || (hasCredentials && credentials[key]) {
+			continue
+		}
+		from, to := src.Field(i), dst.Field(i)
+		if from.IsZero() {
+			continue
+		}
+		if isStructPointer(from) {
+			merged := reflect.New(from.Elem().Type())
+			if !to.IsNil() {
+				merged.Elem().Set(to.Elem())
+			}
+			merge(merged.Elem(), from.Elem(), key+".", source, sources)
+			to.Set(merged)
+			continue
+		}
+		if !to.IsZero() {
+			continue
+		}
+		to.Set(from)
+		sources[key] = source
+	}
+}
+
+//hasAny checks whether any of given top level settings is set
+func hasAny(v reflect.Value, keys map[string]bool) bool {
+	for i := 0; i < v.NumField(); i++ {
+		if keys[yamlKey(v.Type().Field(i))] && !v.Field(i).IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+func isStructPointer(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct
+}
+
+//yamlKey returns key of field in config file
+func yamlKey(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if len(tag) > 0 {
+		return tag
+	}
+	return strings.ToLower(field.Name)
+}
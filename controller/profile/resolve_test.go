@@ -0,0 +1,162 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package profile
+
+import (
+	config "opensearch-cli/controller/config/mocks"
+	"opensearch-cli/entity"
+	"opensearch-cli/mapper"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func getInheritedConfig() entity.Config {
+	ca := "/etc/opensearch/root-ca.pem"
+	clientCert := "/etc/opensearch/client.pem"
+	maxRetry := 3
+	timeout := int64(10)
+	prodTimeout := int64(30)
+	return entity.Config{
+		Defaults: &entity.Profile{
+			Certificate: &entity.Trust{CAFilePath: &ca, MinTLSVersion: "1.2"},
+			MaxRetry:    &maxRetry,
+			Timeout:     &timeout,
+		},
+		Profiles: []entity.Profile{
+			{
+				Name:        "base",
+				UserName:    "admin",
+				Password:    "admin",
+				Certificate: &entity.Trust{ClientCertificateFilePath: &clientCert},
+			},
+			{
+				Name:     "prod",
+				Extends:  "base",
+				Endpoint: "https://prod:9200",
+				Timeout:  &prodTimeout,
+			},
+			{
+				Name:     "prod-eu",
+				Extends:  "prod",
+				Endpoint: "https://prod-eu:9200",
+			},
+		}}
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("profile inherits from extended profiles and defaults", func(t *testing.T) {
+		data := getInheritedConfig()
+		actual, err := resolve(data, "prod-eu")
+		assert.NoError(t, err)
+		assert.EqualValues(t, entity.Profile{
+			Name:     "prod-eu",
+			Extends:  "prod",
+			Endpoint: "https://prod-eu:9200",
+			UserName: "admin",
+			Password: "admin",
+			Certificate: &entity.Trust{
+				CAFilePath:                data.Defaults.Certificate.CAFilePath,
+				ClientCertificateFilePath: data.Profiles[0].Certificate.ClientCertificateFilePath,
+				MinTLSVersion:             "1.2",
+			},
+			MaxRetry: data.Defaults.MaxRetry,
+			Timeout:  data.Profiles[1].Timeout,
+		}, actual.Profile)
+		assert.EqualValues(t, map[string]string{
+			"endpoint":                              "prod-eu",
+			"user":                                  "base",
+			"password":                              "base",
+			"certificate.clientcertificatefilepath": "base",
+			"certificate.cafilepath":                "",
+			"certificate.min_tls_version":           "",
+			"max_retry":                             "",
+			"timeout":                               "prod",
+		}, actual.Sources)
+	})
+	t.Run("credentials are inherited as a group", func(t *testing.T) {
+		data := getInheritedConfig()
+		data.Profiles[2].Token = &entity.Token{Command: "get-token"}
+		actual, err := resolve(data, "prod-eu")
+		assert.NoError(t, err)
+		assert.EqualValues(t, data.Profiles[2].Token, actual.Token)
+		assert.Empty(t, actual.UserName)
+		assert.Empty(t, actual.Password)
+		assert.NotContains(t, actual.Sources, "user")
+		assert.NotContains(t, actual.Sources, "password")
+	})
+	t.Run("boolean setting overrides extended profile", func(t *testing.T) {
+		data := getInheritedConfig()
+		data.Profiles[0].Certificate.Insecure = mapper.BoolToBoolPtr(true)
+		data.Profiles[2].Certificate = &entity.Trust{Insecure: mapper.BoolToBoolPtr(false)}
+		actual, err := resolve(data, "prod-eu")
+		assert.NoError(t, err)
+		assert.EqualValues(t, mapper.BoolToBoolPtr(false), actual.Certificate.Insecure)
+		assert.EqualValues(t, "prod-eu", actual.Sources["certificate.insecure"])
+	})
+	t.Run("inherited settings are not shared", func(t *testing.T) {
+		data := getInheritedConfig()
+		actual, err := resolve(data, "prod")
+		assert.NoError(t, err)
+		actual.Certificate.MinTLSVersion = "1.3"
+		assert.EqualValues(t, "1.2", data.Defaults.Certificate.MinTLSVersion)
+		assert.Nil(t, data.Profiles[0].Certificate.CAFilePath)
+	})
+	t.Run("profile without extends and defaults", func(t *testing.T) {
+		actual, err := resolve(getSampleConfig(), "local")
+		assert.NoError(t, err)
+		assert.EqualValues(t, getSampleConfig().Profiles[0], actual.Profile)
+	})
+	t.Run("profile doesn't exist", func(t *testing.T) {
+		_, err := resolve(getInheritedConfig(), "dev")
+		assert.EqualError(t, err, "profile 'dev' does not exist")
+	})
+	t.Run("extended profile doesn't exist", func(t *testing.T) {
+		data := getInheritedConfig()
+		data.Profiles[1].Extends = "missing"
+		_, err := resolve(data, "prod-eu")
+		assert.EqualError(t, err, "profile 'prod' extends 'missing' which does not exist")
+	})
+	t.Run("cycle", func(t *testing.T) {
+		data := getInheritedConfig()
+		data.Profiles[0].Extends = "prod-eu"
+		_, err := resolve(data, "prod-eu")
+		assert.EqualError(t, err, "profile 'prod-eu' cannot be resolved, profile 'base' extends 'prod-eu' which forms a cycle")
+	})
+}
+
+func TestControllerResolvedProfile(t *testing.T) {
+	t.Run("profile for execution is resolved", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		mockConfigCtrl.EXPECT().Read().Return(getInheritedConfig(), nil)
+		ctrl := New(mockConfigCtrl)
+		actual, ok, err := ctrl.GetProfileForExecution("prod")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.EqualValues(t, "https://prod:9200", actual.Endpoint)
+		assert.EqualValues(t, "admin", actual.UserName)
+		assert.EqualValues(t, 3, *actual.MaxRetry)
+	})
+	t.Run("rename updates extends", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockConfigCtrl := config.NewMockController(mockCtrl)
+		expectLock(mockConfigCtrl)
+		mockConfigCtrl.EXPECT().ReadWritable().Return(getInheritedConfig(), nil)
+		expected := getInheritedConfig()
+		expected.Profiles[0].Name = "shared"
+		expected.Profiles[1].Extends = "shared"
+		mockConfigCtrl.EXPECT().Write(expected).Return(nil)
+		ctrl := New(mockConfigCtrl)
+		assert.NoError(t, ctrl.RenameProfile("base", "shared"))
+	})
+}
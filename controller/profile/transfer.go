@@ -0,0 +1,202 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package profile
+
+import (
+	"fmt"
+	"net/url"
+	"opensearch-cli/controller/config"
+	"opensearch-cli/entity"
+	"strings"
+)
+
+//SecretPolicy decides how secrets of profiles are exported
+type SecretPolicy string
+
+//ConflictPolicy decides how imported profile is saved if a profile with same name already exists
+type ConflictPolicy string
+
+const (
+	//SecretsStrip removes secrets from exported profiles
+	SecretsStrip SecretPolicy = "strip"
+	//SecretsRedact replaces secrets of exported profiles with RedactedSecret, so that reader knows secret is required
+	SecretsRedact SecretPolicy = "redact"
+	//RedactedSecret replaces secrets of exported profiles, redacted secrets are removed on import
+	RedactedSecret = "********"
+
+	//ConflictFail fails import without saving any profile
+	ConflictFail ConflictPolicy = "fail"
+	//ConflictOverwrite replaces existing profile with imported profile
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	//ConflictSkip keeps existing profile and ignores imported profile
+	ConflictSkip ConflictPolicy = "skip"
+	//ConflictRename saves imported profile with new name
+	ConflictRename ConflictPolicy = "rename"
+
+	renameSuffix = "-imported"
+)
+
+//ImportResult lists names of imported profiles by how they are saved
+type ImportResult struct {
+	Created     []string
+	Overwritten []string
+	Skipped     []string
+	//Renamed maps name of imported profile to the name it is saved with
+	Renamed map[string]string
+}
+
+//ExportProfiles returns profiles by names, or every profile if names are not provided, secrets are removed
+//or redacted based on policy. Profiles are resolved with profiles they extend and defaults of config file,
+//since those are not exported
+func (c controller) ExportProfiles(names []string, policy SecretPolicy) ([]entity.Profile, error) {
+	if policy != SecretsStrip && policy != SecretsRedact {
+		return nil, fmt.Errorf("invalid secret policy '%s'", policy)
+	}
+	data, err := c.configCtrl.Read()
+	if err != nil {
+		return nil, err
+	}
+	profiles := data.Profiles
+	if len(names) > 0 {
+		var selected []entity.Profile
+		var invalidProfileNames []string
+		for _, name := range names {
+			index := findProfile(profiles, name)
+			if index < 0 {
+				invalidProfileNames = append(invalidProfileNames, name)
+				continue
+			}
+			selected = append(selected, profiles[index])
+		}
+		if len(invalidProfileNames) > 0 {
+			return nil, fmt.Errorf("no profiles found for: %s", strings.Join(invalidProfileNames, ", "))
+		}
+		profiles = selected
+	}
+	result := make([]entity.Profile, 0, len(profiles))
+	for _, own := range profiles {
+		resolved, err := resolve(data, own.Name)
+		if err != nil {
+			return nil, err
+		}
+		p := resolved.Profile
+		p.Extends = ""
+		for _, secret := range secrets(&p) {
+			if len(*secret) == 0 {
+				continue
+			}
+			*secret = ""
+			if policy == SecretsRedact {
+				*secret = RedactedSecret
+			}
+		}
+		if p.Proxy != nil {
+			p.Proxy.URL = removeURLPassword(p.Proxy.URL)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+//ImportProfiles saves profiles in config file, profiles whose name is already used are saved based on policy.
+//Redacted secrets are removed, and secrets are encrypted if cipher is available
+func (c controller) ImportProfiles(profiles []entity.Profile, policy ConflictPolicy) (result ImportResult, err error) {
+	switch policy {
+	case ConflictFail, ConflictOverwrite, ConflictSkip, ConflictRename:
+	default:
+		return result, fmt.Errorf("invalid conflict policy '%s'", policy)
+	}
+	for i, p := range profiles {
+		if len(p.Name) < 1 {
+			return result, fmt.Errorf("profile name cannot be empty")
+		}
+		if findProfile(profiles[:i], p.Name) >= 0 {
+			return result, fmt.Errorf("profile %s is defined more than once", p.Name)
+		}
+	}
+	err = c.updateConfig(func(data *entity.Config) error {
+		result = ImportResult{Renamed: map[string]string{}}
+		var conflicts []string
+		for _, p := range profiles {
+			if findProfile(data.Profiles, p.Name) >= 0 {
+				conflicts = append(conflicts, p.Name)
+			}
+		}
+		if len(conflicts) > 0 && policy == ConflictFail {
+			return fmt.Errorf("profiles already exist: %s", strings.Join(conflicts, ", "))
+		}
+		imported := make([]entity.Profile, 0, len(profiles))
+		for _, p := range profiles {
+			for _, secret := range secrets(&p) {
+				//secrets encrypted by exporter cannot be decrypted with local key
+				if *secret == RedactedSecret || config.IsEncrypted(*secret) {
+					*secret = ""
+				}
+			}
+			if err := c.encryptSecrets(&p); err != nil {
+				return err
+			}
+			index := findProfile(data.Profiles, p.Name)
+			switch {
+			case index < 0:
+				result.Created = append(result.Created, p.Name)
+			case policy == ConflictSkip:
+				result.Skipped = append(result.Skipped, p.Name)
+				continue
+			case policy == ConflictOverwrite:
+				result.Overwritten = append(result.Overwritten, p.Name)
+				data.Profiles[index] = p
+				continue
+			case policy == ConflictRename:
+				newName := availableName(data.Profiles, profiles, p.Name)
+				result.Renamed[p.Name] = newName
+				p.Name = newName
+			}
+			imported = append(imported, p)
+		}
+		//imported profiles which extend renamed profiles should extend them by new name
+		for i := range imported {
+			if newName, ok := result.Renamed[imported[i].Extends]; ok {
+				imported[i].Extends = newName
+			}
+		}
+		if len(result.Created) == 0 && len(result.Overwritten) == 0 && len(result.Renamed) == 0 {
+			return errNotModified
+		}
+		data.Profiles = append(data.Profiles, imported...)
+		return nil
+	})
+	return result, err
+}
+
+//availableName returns name for renamed profile, which is not used by existing or imported profiles
+func availableName(existing []entity.Profile, imported []entity.Profile, name string) string {
+	candidate := name + renameSuffix
+	for i := 2; findProfile(existing, candidate) >= 0 || findProfile(imported, candidate) >= 0; i++ {
+		candidate = fmt.Sprintf("%s%s-%d", name, renameSuffix, i)
+	}
+	return candidate
+}
+
+//removeURLPassword removes password from user info of rawURL under every secret policy, since password cannot be
+//redacted in URL without being escaped. URL which cannot be parsed is removed, since it may contain password
+func removeURLPassword(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	if u.User == nil {
+		return rawURL
+	}
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
+	}
+	u.User = url.User(u.User.Username())
+	return u.String()
+}
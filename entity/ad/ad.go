@@ -27,6 +27,7 @@ package ad
 
 import (
 	"encoding/json"
+	"fmt"
 	"opensearch-cli/entity"
 )
 
@@ -109,6 +110,18 @@ type CreateError struct {
 	Status int32             `json:"status"`
 }
 
+//PartialFailureError is returned when action on detectors matched by name pattern failed for some detectors
+type PartialFailureError struct {
+	Action string
+	Total  int
+	Failed []string
+}
+
+//Error inherits error interface to pass as error
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("failed to %s %d/%d detector(s)", e.Action, len(e.Failed), e.Total)
+}
+
 //Configuration represents configuration in config file
 type Configuration struct {
 	Profiles []entity.Profile `mapstructure:"profiles"`
@@ -25,7 +25,9 @@
 
 package entity
 
-//Config represents config file structure
+//Config represents config file structure, Defaults contains settings shared by every profile
 type Config struct {
-	Profiles []Profile `yaml:"profiles"`
+	Version  int       `yaml:"version" json:"version"`
+	Defaults *Profile  `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	Profiles []Profile `yaml:"profiles" json:"profiles"`
 }
@@ -0,0 +1,20 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package entity
+
+import "time"
+
+//Credentials represents json printed by credential process, either username and password or token is required.
+//If Expiration is provided, credentials are cached until they expire, else, for the duration of command
+type Credentials struct {
+	UserName   string     `json:"username,omitempty"`
+	Password   string     `json:"password,omitempty"`
+	Token      string     `json:"token,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
@@ -87,3 +87,56 @@ type CurlCommandRequest struct {
 	OutputFormat     string
 	OutputFilterPath string
 }
+
+//ClusterVersion contains version details returned by cluster root endpoint
+type ClusterVersion struct {
+	Number       string `json:"number"`
+	Distribution string `json:"distribution,omitempty"`
+}
+
+//ClusterInfo represents response of cluster root endpoint
+type ClusterInfo struct {
+	Name        string         `json:"name"`
+	ClusterName string         `json:"cluster_name"`
+	Version     ClusterVersion `json:"version"`
+}
+
+//Plugin represents plugin installed on a node
+type Plugin struct {
+	Node      string `json:"name"`
+	Component string `json:"component"`
+	Version   string `json:"version"`
+}
+
+//AuthInfo represents authenticated user returned by security plugin
+type AuthInfo struct {
+	UserName string `json:"user_name"`
+}
+
+//NodesHTTP represents response of nodes info API filtered to roles and http publish address of nodes
+type NodesHTTP struct {
+	Nodes map[string]NodeHTTP `json:"nodes"`
+}
+
+//NodeHTTP contains roles of node and address to send http requests to, publish address is either
+//host:port or hostname/ip:port
+type NodeHTTP struct {
+	Roles []string `json:"roles"`
+	HTTP  struct {
+		PublishAddress string `json:"publish_address"`
+	} `json:"http"`
+}
+
+//ConnectionReport contains outcome of connectivity and authentication check against cluster
+type ConnectionReport struct {
+	Endpoint         string   `json:"endpoint"`
+	Reachable        bool     `json:"reachable"`
+	TLS              string   `json:"tls"`
+	User             string   `json:"user,omitempty"`
+	ClusterName      string   `json:"cluster_name,omitempty"`
+	Version          string   `json:"version,omitempty"`
+	AnomalyDetection bool     `json:"anomaly_detection"`
+	KNN              bool     `json:"knn"`
+	Plugins          []string `json:"plugins,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
@@ -35,14 +35,24 @@ import (
 //better error message
 type RequestError struct {
 	statusCode int
+	url        string
 	err        error
 	response   []byte
 }
 
+//errorResponse represents error response returned by OpenSearch
+type errorResponse struct {
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
 //NewRequestError builds RequestError
-func NewRequestError(statusCode int, body io.ReadCloser, err error) *RequestError {
+func NewRequestError(statusCode int, url string, body io.ReadCloser, err error) *RequestError {
 	return &RequestError{
 		statusCode: statusCode,
+		url:        url,
 		err:        err,
 		response:   getResponseBody(body),
 	}
@@ -58,6 +68,29 @@ func (r *RequestError) StatusCode() int {
 	return r.statusCode
 }
 
+//URL to get url of the failed request
+func (r *RequestError) URL() string {
+	return r.url
+}
+
+//ErrorType to get error type from OpenSearch error response, if available
+func (r *RequestError) ErrorType() string {
+	var data errorResponse
+	if err := json.Unmarshal(r.response, &data); err != nil {
+		return ""
+	}
+	return data.Error.Type
+}
+
+//ErrorReason to get error reason from OpenSearch error response, if available
+func (r *RequestError) ErrorReason() string {
+	var data errorResponse
+	if err := json.Unmarshal(r.response, &data); err != nil {
+		return ""
+	}
+	return data.Error.Reason
+}
+
 //GetResponse to get error response from OpenSearch
 func (r *RequestError) GetResponse() string {
 	var data map[string]interface{}
@@ -76,3 +109,24 @@ func getResponseBody(b io.Reader) []byte {
 	}
 	return resBytes
 }
+
+//ResponseError uses error response from OpenSearch as error message, underlying
+//RequestError is still accessible using errors.As
+type ResponseError struct {
+	*RequestError
+}
+
+//NewResponseError builds ResponseError from RequestError
+func NewResponseError(r *RequestError) *ResponseError {
+	return &ResponseError{r}
+}
+
+//Error returns error response from OpenSearch as error message
+func (r *ResponseError) Error() string {
+	return r.GetResponse()
+}
+
+//Unwrap returns underlying RequestError
+func (r *ResponseError) Unwrap() error {
+	return r.RequestError
+}
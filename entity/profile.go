@@ -25,25 +25,100 @@
 
 package entity
 
+//AWSIAM contains settings to sign requests using AWS credentials. Credentials of AWS profile, or environment variables,
+//are used to assume RoleARN if it is set. If WebIdentityTokenFile is set, RoleARN is assumed using the web identity token
 type AWSIAM struct {
-	ProfileName string `yaml:"profile"`
-	ServiceName string `yaml:"service"`
+	ProfileName          string `yaml:"profile" json:"profile"`
+	ServiceName          string `yaml:"service" json:"service"`
+	Region               string `yaml:"region,omitempty" json:"region,omitempty"`
+	RoleARN              string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+	ExternalID           string `yaml:"external_id,omitempty" json:"external_id,omitempty"`
+	SessionName          string `yaml:"session_name,omitempty" json:"session_name,omitempty"`
+	WebIdentityTokenFile string `yaml:"web_identity_token_file,omitempty" json:"web_identity_token_file,omitempty"`
 }
 
-//Trust contains file path for certificate and private key locations
+//Trust contains file path for certificate and private key locations, and settings to verify server's certificate.
+//CA certificate is trusted in addition to system's certificate pool. If Insecure is true, server's certificate is not verified
 type Trust struct {
-	CAFilePath                *string
-	ClientCertificateFilePath *string
-	ClientKeyFilePath         *string
+	CAFilePath                *string `json:"cafilepath,omitempty"`
+	ClientCertificateFilePath *string `json:"clientcertificatefilepath,omitempty"`
+	ClientKeyFilePath         *string `json:"clientkeyfilepath,omitempty"`
+	Insecure                  *bool   `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+	ServerName                string  `yaml:"server_name,omitempty" json:"server_name,omitempty"`
+	MinTLSVersion             string  `yaml:"min_tls_version,omitempty" json:"min_tls_version,omitempty"`
 }
 
+//Proxy contains proxy server used to connect to cluster, credentials are optional
+type Proxy struct {
+	URL      string `yaml:"url" json:"url"`
+	UserName string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+//Transport contains connection pool and keep-alive settings, durations are in seconds
+type Transport struct {
+	MaxIdleConns        *int   `yaml:"max_idle_conns,omitempty" json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost *int   `yaml:"max_idle_conns_per_host,omitempty" json:"max_idle_conns_per_host,omitempty"`
+	MaxConnsPerHost     *int   `yaml:"max_conns_per_host,omitempty" json:"max_conns_per_host,omitempty"`
+	IdleConnTimeout     *int64 `yaml:"idle_conn_timeout,omitempty" json:"idle_conn_timeout,omitempty"`
+	KeepAlive           *int64 `yaml:"keep_alive,omitempty" json:"keep_alive,omitempty"`
+	DisableKeepAlives   *bool  `yaml:"disable_keep_alives,omitempty" json:"disable_keep_alives,omitempty"`
+}
+
+//Retry contains policy to retry failed requests, backoff durations are in milliseconds. POST and PATCH requests, except
+//searches, are not retried unless RetryNonIdempotent is true
+type Retry struct {
+	MinBackoff         *int64 `yaml:"min_backoff,omitempty" json:"min_backoff,omitempty"`
+	MaxBackoff         *int64 `yaml:"max_backoff,omitempty" json:"max_backoff,omitempty"`
+	Jitter             *bool  `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+	StatusCodes        []int  `yaml:"status_codes,omitempty" json:"status_codes,omitempty"`
+	HonorRetryAfter    *bool  `yaml:"honor_retry_after,omitempty" json:"honor_retry_after,omitempty"`
+	RetryNonIdempotent *bool  `yaml:"retry_non_idempotent,omitempty" json:"retry_non_idempotent,omitempty"`
+}
+
+//Sniff contains settings to discover data and coordinating nodes of cluster, discovered nodes are cached
+//for TTL seconds
+type Sniff struct {
+	Enabled *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	TTL     *int64 `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+}
+
+//Token contains bearer token used to authenticate, either as static value or command which prints the token on stdout
+type Token struct {
+	Value   string `yaml:"value,omitempty" json:"value,omitempty"`
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+}
+
+//Profile contains settings and credentials to connect to cluster. If Extends is set, settings which are not
+//set by profile are inherited from the extended profile, and then, from defaults of config file.
+//Endpoints are nodes of the same cluster used along with Endpoint, EndpointSelection decides how requests are
+//distributed between them, either failover (default) or round-robin. If Sniff is enabled, requests are sent to
+//nodes discovered from cluster instead
 type Profile struct {
-	Name        string  `yaml:"name"`
-	Endpoint    string  `yaml:"endpoint"`
-	UserName    string  `yaml:"user,omitempty"`
-	Password    string  `yaml:"password,omitempty"`
-	AWS         *AWSIAM `yaml:"aws_iam,omitempty"`
-	Certificate *Trust  `yaml:"certificate,omitempty"`
-	MaxRetry    *int    `yaml:"max_retry,omitempty"`
-	Timeout     *int64  `yaml:"timeout,omitempty"`
+	Name              string     `yaml:"name,omitempty" json:"name,omitempty"`
+	Extends           string     `yaml:"extends,omitempty" json:"extends,omitempty"`
+	Endpoint          string     `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Endpoints         []string   `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+	EndpointSelection string     `yaml:"endpoint_selection,omitempty" json:"endpoint_selection,omitempty"`
+	Sniff             *Sniff     `yaml:"sniff,omitempty" json:"sniff,omitempty"`
+	UserName          string     `yaml:"user,omitempty" json:"user,omitempty"`
+	Password          string     `yaml:"password,omitempty" json:"password,omitempty"`
+	Token             *Token     `yaml:"token,omitempty" json:"token,omitempty"`
+	APIKey            string     `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	CredentialProcess string     `yaml:"credential_process,omitempty" json:"credential_process,omitempty"`
+	AWS               *AWSIAM    `yaml:"aws_iam,omitempty" json:"aws_iam,omitempty"`
+	Certificate       *Trust     `yaml:"certificate,omitempty" json:"certificate,omitempty"`
+	MaxRetry          *int       `yaml:"max_retry,omitempty" json:"max_retry,omitempty"`
+	Retry             *Retry     `yaml:"retry,omitempty" json:"retry,omitempty"`
+	Timeout           *int64     `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Proxy             *Proxy     `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	Transport         *Transport `yaml:"transport,omitempty" json:"transport,omitempty"`
+}
+
+//ResolvedProfile is a profile merged with profiles it extends and defaults of config file.
+//Sources maps every setting, as dotted yaml key, to name of the profile which defines it, settings
+//inherited from defaults of config file are mapped to empty string
+type ResolvedProfile struct {
+	Profile
+	Sources map[string]string `yaml:"-" json:"sources"`
 }
@@ -25,10 +25,14 @@
 package environment
 
 const (
-	OPENSEARCH_ENDPOINT  = "OPENSEARCH_ENDPOINT"
-	OPENSEARCH_MAX_RETRY = "OPENSEARCH_MAX_RETRY"
-	OPENSEARCH_PASSWORD  = "OPENSEARCH_PASSWORD"
-	OPENSEARCH_PROFILE   = "OPENSEARCH_PROFILE"
-	OPENSEARCH_TIMEOUT   = "OPENSEARCH_TIMEOUT"
-	OPENSEARCH_USER      = "OPENSEARCH_USER"
+	OPENSEARCH_ASSUME_YES     = "OPENSEARCH_ASSUME_YES"
+	OPENSEARCH_CLI_KEY_FILE   = "OPENSEARCH_CLI_KEY_FILE"
+	OPENSEARCH_CLI_PASSPHRASE = "OPENSEARCH_CLI_PASSPHRASE"
+	OPENSEARCH_ENDPOINT       = "OPENSEARCH_ENDPOINT"
+	OPENSEARCH_MAX_RETRY      = "OPENSEARCH_MAX_RETRY"
+	OPENSEARCH_NO_INPUT       = "OPENSEARCH_NO_INPUT"
+	OPENSEARCH_PASSWORD       = "OPENSEARCH_PASSWORD"
+	OPENSEARCH_PROFILE        = "OPENSEARCH_PROFILE"
+	OPENSEARCH_TIMEOUT        = "OPENSEARCH_TIMEOUT"
+	OPENSEARCH_USER           = "OPENSEARCH_USER"
 )
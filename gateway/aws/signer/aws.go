@@ -29,40 +29,133 @@ import (
 	"bytes"
 	"errors"
 	"opensearch-cli/entity"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
+const (
+	defaultSessionName = "opensearch-cli"
+	//expiryWindow refreshes assumed role credentials before they expire, so that request is not signed using
+	//credentials which expire before request reaches the cluster
+	expiryWindow = time.Minute
+)
+
+//Signer signs requests using SigV4 for AWS IAM settings. Signer is safe for concurrent use, and should be
+//shared between requests, so that AWS session is created and credentials are retrieved only once
+type Signer struct {
+	signer  *v4.Signer
+	region  string
+	service string
+}
+
+//cache shares signers between gateways which use same AWS IAM settings, so that credentials,
+//like assumed role credentials, are retrieved once and refreshed only when they expire
+var (
+	cacheLock sync.Mutex
+	cache     = map[entity.AWSIAM]*Signer{}
+)
+
 func GetV4Signer(credentials *credentials.Credentials) *v4.Signer {
 	return v4.NewSigner(credentials)
 }
-func sign(req *retryablehttp.Request, region *string, serviceName string, signer *v4.Signer) error {
-	bodyBytes, err := req.BodyBytes()
-	if err != nil {
-		return err
+
+//Get returns signer for AWS IAM settings from cache, signer is created if it is not cached
+func Get(awsProfile entity.AWSIAM) (*Signer, error) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if cached, ok := cache[awsProfile]; ok {
+		return cached, nil
 	}
-	if region == nil || len(*region) == 0 {
-		return errors.New("aws region is not found. Either set 'AWS_REGION' or add this information during aws profile creation step")
+	result, err := New(awsProfile, GetV4Signer)
+	if err != nil {
+		return nil, err
 	}
-	// Sign the request
-	_, err = signer.Sign(req.Request, bytes.NewReader(bodyBytes), serviceName, *region, time.Now())
-	return err
+	cache[awsProfile] = result
+	return result, nil
 }
 
-//SignRequest signs the request using SigV4
-func SignRequest(req *retryablehttp.Request, awsProfile entity.AWSIAM, getSigner func(*credentials.Credentials) *v4.Signer) error {
+//New creates signer using credentials from AWS profile, or environment variables if profile name is empty.
+//If role ARN is set, credentials assume the role, using web identity token if token file is set
+func New(awsProfile entity.AWSIAM, getSigner func(*credentials.Credentials) *v4.Signer) (*Signer, error) {
+	if len(awsProfile.WebIdentityTokenFile) > 0 && len(awsProfile.RoleARN) == 0 {
+		return nil, errors.New("aws role ARN is required to assume role using web identity token file")
+	}
+	var config aws.Config
+	if len(awsProfile.Region) > 0 {
+		config.Region = aws.String(awsProfile.Region)
+	}
 	awsSession, err := session.NewSessionWithOptions(session.Options{
 		Profile:           awsProfile.ProfileName,
 		SharedConfigState: session.SharedConfigEnable,
+		Config:            config,
 	})
+	if err != nil {
+		return nil, err
+	}
+	region := aws.StringValue(awsSession.Config.Region)
+	if len(region) == 0 {
+		return nil, errors.New("aws region is not found. Either set 'AWS_REGION' or add this information during aws profile creation step")
+	}
+	return &Signer{
+		signer:  getSigner(newCredentials(awsSession, awsProfile)),
+		region:  region,
+		service: awsProfile.ServiceName,
+	}, nil
+}
+
+//newCredentials returns credentials of session, or credentials of assumed role if role ARN is set
+func newCredentials(awsSession *session.Session, awsProfile entity.AWSIAM) *credentials.Credentials {
+	sessionName := awsProfile.SessionName
+	if len(sessionName) == 0 {
+		sessionName = defaultSessionName
+	}
+	switch {
+	case len(awsProfile.WebIdentityTokenFile) > 0:
+		provider := stscreds.NewWebIdentityRoleProvider(sts.New(awsSession), awsProfile.RoleARN, sessionName, awsProfile.WebIdentityTokenFile)
+		provider.ExpiryWindow = expiryWindow
+		return credentials.NewCredentials(provider)
+	case len(awsProfile.RoleARN) > 0:
+		return stscreds.NewCredentials(awsSession, awsProfile.RoleARN, func(provider *stscreds.AssumeRoleProvider) {
+			provider.RoleSessionName = sessionName
+			provider.ExpiryWindow = expiryWindow
+			if len(awsProfile.ExternalID) > 0 {
+				provider.ExternalID = aws.String(awsProfile.ExternalID)
+			}
+		})
+	}
+	return awsSession.Config.Credentials
+}
+
+//Sign signs the request using SigV4. Requests to OpenSearch Serverless include hash of payload, and
+//fail without being sent if API is not supported by OpenSearch Serverless
+func (s *Signer) Sign(req *retryablehttp.Request) error {
+	bodyBytes, err := req.BodyBytes()
+	if err != nil {
+		return err
+	}
+	if s.service == ServiceServerless {
+		if err = checkServerlessSupport(req.URL.Path); err != nil {
+			return err
+		}
+		req.Header.Set(contentSHA256Header, contentSHA256(bodyBytes))
+	}
+	_, err = s.signer.Sign(req.Request, bytes.NewReader(bodyBytes), s.service, s.region, time.Now())
+	return err
+}
 
+//SignRequest signs the request using SigV4, signer is created for every request, use Get to share signer between requests
+func SignRequest(req *retryablehttp.Request, awsProfile entity.AWSIAM, getSigner func(*credentials.Credentials) *v4.Signer) error {
+	signer, err := New(awsProfile, getSigner)
 	if err != nil {
 		return err
 	}
-	signer := getSigner(awsSession.Config.Credentials)
-	return sign(req, awsSession.Config.Region, awsProfile.ServiceName, signer)
+	return signer.Sign(req)
 }
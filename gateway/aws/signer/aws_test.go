@@ -43,8 +43,16 @@ func buildSigner() *v4.Signer {
 	}
 }
 
+//resetCache removes cached credentials, so that credentials are created using current environment variables
+func resetCache() {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	cache = map[entity.AWSIAM]*Signer{}
+}
+
 func TestV4Signer(t *testing.T) {
 	t.Run("sign request success", func(t *testing.T) {
+		resetCache()
 		req, _ := retryablehttp.NewRequest(http.MethodGet, "https://localhost:9200", nil)
 		region := os.Getenv("AWS_REGION")
 		os.Setenv("AWS_REGION", "us-west-2")
@@ -63,6 +71,7 @@ func TestV4Signer(t *testing.T) {
 		assert.NotEmpty(t, q.Get("X-Amz-Date"))
 	})
 	t.Run("sign request failed due to no region found", func(t *testing.T) {
+		resetCache()
 		req, _ := retryablehttp.NewRequest(http.MethodGet, "https://localhost:9200", nil)
 		region := os.Getenv("AWS_REGION")
 		os.Setenv("AWS_REGION", "")
@@ -79,3 +88,87 @@ func TestV4Signer(t *testing.T) {
 			t, err, "aws region is not found. Either set 'AWS_REGION' or add this information during aws profile creation step", "unexpected error")
 	})
 }
+
+func TestSigner(t *testing.T) {
+	t.Run("region of aws iam settings takes precedence", func(t *testing.T) {
+		region := os.Getenv("AWS_REGION")
+		os.Setenv("AWS_REGION", "us-west-2")
+		defer func() {
+			os.Setenv("AWS_REGION", region)
+		}()
+		actual, err := New(entity.AWSIAM{ServiceName: "es", Region: "eu-west-1"}, GetV4Signer)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "eu-west-1", actual.region)
+	})
+	t.Run("signer is shared", func(t *testing.T) {
+		resetCache()
+		awsIAM := entity.AWSIAM{ServiceName: "es", Region: "eu-west-1", RoleARN: "arn:aws:iam::123456789012:role/search"}
+		first, err := Get(awsIAM)
+		assert.NoError(t, err)
+		second, err := Get(awsIAM)
+		assert.NoError(t, err)
+		assert.Same(t, first, second)
+		awsIAM.ExternalID = "external-id"
+		other, err := Get(awsIAM)
+		assert.NoError(t, err)
+		assert.NotSame(t, first, other)
+	})
+	t.Run("shared signer signs request", func(t *testing.T) {
+		resetCache()
+		s, err := New(entity.AWSIAM{ServiceName: "es", Region: "eu-west-1"}, func(c *credentials.Credentials) *v4.Signer {
+			return buildSigner()
+		})
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			req, _ := retryablehttp.NewRequest(http.MethodPost, "https://localhost:9200/_bulk", []byte(`{"index":{}}`))
+			assert.NoError(t, s.Sign(req))
+			assert.Contains(t, req.Header.Get("Authorization"), "/eu-west-1/es/aws4_request")
+		}
+	})
+	t.Run("web identity requires role", func(t *testing.T) {
+		_, err := Get(entity.AWSIAM{ServiceName: "es", Region: "eu-west-1", WebIdentityTokenFile: "/var/run/token"})
+		assert.EqualError(t, err, "aws role ARN is required to assume role using web identity token file")
+	})
+	t.Run("web identity token file is used", func(t *testing.T) {
+		actual, err := New(entity.AWSIAM{
+			ServiceName:          "es",
+			Region:               "eu-west-1",
+			RoleARN:              "arn:aws:iam::123456789012:role/search",
+			WebIdentityTokenFile: "/non-existing/token",
+		}, GetV4Signer)
+		assert.NoError(t, err)
+		_, err = actual.signer.Credentials.Get()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed fetching WebIdentity token")
+	})
+}
+
+func BenchmarkSignRequest(b *testing.B) {
+	awsIAM := entity.AWSIAM{ServiceName: "es", Region: "us-west-2"}
+	getSigner := func(c *credentials.Credentials) *v4.Signer {
+		return buildSigner()
+	}
+	newRequest := func() *retryablehttp.Request {
+		req, _ := retryablehttp.NewRequest(http.MethodPost, "https://localhost:9200/_plugins/_anomaly_detection/detectors/id/_start", nil)
+		return req
+	}
+	b.Run("new session per request", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := SignRequest(newRequest(), awsIAM, getSigner); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("shared signer", func(b *testing.B) {
+		s, err := New(awsIAM, getSigner)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := s.Sign(newRequest()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+//Service names supported by signer
+const (
+	ServiceOpenSearch = "es"
+	ServiceServerless = "aoss"
+)
+
+//anySegment matches any single path segment in unsupportedServerlessAPIs, like node ids
+const anySegment = "*"
+
+//contentSHA256Header is required by OpenSearch Serverless, which doesn't compute hash of payload itself
+const contentSHA256Header = "X-Amz-Content-Sha256"
+
+//unsupportedServerlessAPIs are path prefixes of APIs which are not available in OpenSearch Serverless collections,
+//since cluster, nodes and shards are managed by AWS
+var unsupportedServerlessAPIs = [][]string{
+	{"_cluster"},
+	{"_nodes"},
+	{"_snapshot"},
+	{"_tasks"},
+	{"_cat", "nodes"},
+	{"_cat", "plugins"},
+	{"_cat", "shards"},
+	{"_cat", "allocation"},
+	{"_cat", "master"},
+	{"_cat", "cluster_manager"},
+	{"_plugins", "_anomaly_detection"},
+	{"_opendistro", "_anomaly_detection"},
+	{"_plugins", "_knn", "stats"},
+	{"_plugins", "_knn", anySegment, "stats"},
+	{"_plugins", "_knn", "warmup"},
+}
+
+//UnsupportedOperationError represents request which is rejected before sending it, since API is not
+//supported by AWS service of profile
+type UnsupportedOperationError struct {
+	Service string
+	Path    string
+}
+
+//Error inherits error interface to pass as error
+func (e *UnsupportedOperationError) Error() string {
+	return fmt.Sprintf("'%s' is not supported by Amazon OpenSearch Serverless (service '%s'), use an Amazon OpenSearch Service domain instead", e.Path, e.Service)
+}
+
+//ValidateService checks whether AWS service name is provided. Any service name is signed, only ServiceServerless
+//is treated specially
+func ValidateService(name string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return fmt.Errorf("aws service name cannot be empty, use '%s' for Amazon OpenSearch Service and '%s' for Amazon OpenSearch Serverless", ServiceOpenSearch, ServiceServerless)
+	}
+	return nil
+}
+
+//checkServerlessSupport returns error if API of path is not supported by OpenSearch Serverless
+func checkServerlessSupport(path string) error {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, api := range unsupportedServerlessAPIs {
+		if hasPrefix(segments, api) {
+			return &UnsupportedOperationError{Service: ServiceServerless, Path: "/" + strings.Join(segments, "/")}
+		}
+	}
+	return nil
+}
+
+func hasPrefix(segments []string, prefix []string) bool {
+	if len(segments) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != anySegment && segments[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//contentSHA256 returns hex encoded SHA-256 hash of payload
+func contentSHA256(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
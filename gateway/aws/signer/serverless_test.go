@@ -0,0 +1,91 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package signer
+
+import (
+	"net/http"
+	"opensearch-cli/entity"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSigner(t *testing.T, service string) *Signer {
+	s, err := New(entity.AWSIAM{ServiceName: service, Region: "us-east-1"}, func(c *credentials.Credentials) *v4.Signer {
+		return buildSigner()
+	})
+	assert.NoError(t, err)
+	return s
+}
+
+func TestServerlessSigner(t *testing.T) {
+	t.Run("content hash is signed", func(t *testing.T) {
+		req, _ := retryablehttp.NewRequest(http.MethodPost, "https://collection.us-east-1.aoss.amazonaws.com/movies/_search", []byte(`{"query":{"match_all":{}}}`))
+		assert.NoError(t, newTestSigner(t, ServiceServerless).Sign(req))
+		assert.EqualValues(t, "baa6846b65b050d71831bb2e4cd6e6f1593902f6d82b16a6c1f9979d14cfcd12", req.Header.Get("X-Amz-Content-Sha256"))
+		assert.Contains(t, req.Header.Get("Authorization"), "/us-east-1/aoss/aws4_request")
+		assert.Contains(t, req.Header.Get("Authorization"), "x-amz-content-sha256")
+	})
+	t.Run("content hash of empty body", func(t *testing.T) {
+		req, _ := retryablehttp.NewRequest(http.MethodGet, "https://collection.us-east-1.aoss.amazonaws.com/movies/_doc/1", nil)
+		assert.NoError(t, newTestSigner(t, ServiceServerless).Sign(req))
+		assert.EqualValues(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", req.Header.Get("X-Amz-Content-Sha256"))
+	})
+	t.Run("content hash is not added for managed domain", func(t *testing.T) {
+		req, _ := retryablehttp.NewRequest(http.MethodGet, "https://domain.us-east-1.es.amazonaws.com/_cluster/health", nil)
+		assert.NoError(t, newTestSigner(t, ServiceOpenSearch).Sign(req))
+		assert.Empty(t, req.Header.Get("X-Amz-Content-Sha256"))
+	})
+	t.Run("unsupported api", func(t *testing.T) {
+		req, _ := retryablehttp.NewRequest(http.MethodGet, "https://collection.us-east-1.aoss.amazonaws.com/_cluster/health", nil)
+		err := newTestSigner(t, ServiceServerless).Sign(req)
+		assert.EqualError(t, err, "'/_cluster/health' is not supported by Amazon OpenSearch Serverless (service 'aoss'), use an Amazon OpenSearch Service domain instead")
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+}
+
+func TestCheckServerlessSupport(t *testing.T) {
+	tests := []struct {
+		path      string
+		supported bool
+	}{
+		{"/", true},
+		{"/movies/_search", true},
+		{"/_cat/indices", true},
+		{"/_plugins/_knn/models/_search", true},
+		{"/_cluster", false},
+		{"_nodes/stats", false},
+		{"/_cat/nodes", false},
+		{"/_cat/plugins/", false},
+		{"/_plugins/_anomaly_detection/detectors/_search", false},
+		{"/_plugins/_knn/stats", false},
+		{"/_plugins/_knn/node-1,node-2/stats/graph_memory_usage", false},
+		{"/_plugins/_knn/warmup/movies", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			err := checkServerlessSupport(tc.path)
+			if tc.supported {
+				assert.NoError(t, err)
+				return
+			}
+			assert.IsType(t, &UnsupportedOperationError{}, err)
+		})
+	}
+}
+
+func TestValidateService(t *testing.T) {
+	assert.NoError(t, ValidateService("es"))
+	assert.NoError(t, ValidateService("aoss"))
+	assert.NoError(t, ValidateService("ec2"))
+	assert.EqualError(t, ValidateService(" "), "aws service name cannot be empty, use 'es' for Amazon OpenSearch Service and 'aoss' for Amazon OpenSearch Serverless")
+}
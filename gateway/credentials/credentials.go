@@ -0,0 +1,165 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"opensearch-cli/entity"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+//expiryWindow refreshes credentials before they expire, so that request is not sent with expired credentials
+const expiryWindow = time.Minute
+
+const (
+	credentialProcess = "credential process"
+	tokenCommand      = "token command"
+)
+
+//Provider runs credential process, or token command, and caches credentials until they expire
+type Provider struct {
+	kind    string
+	command string
+	mu      sync.Mutex
+	cached  *entity.Credentials
+	run     func(command string) ([]byte, error)
+	parse   func(output []byte) (entity.Credentials, error)
+	now     func() time.Time
+}
+
+type providerKey struct {
+	kind    string
+	command string
+}
+
+var (
+	providersMu sync.Mutex
+	providers   = map[providerKey]*Provider{}
+)
+
+//New returns new Provider instance for credential process, which prints credentials as json
+func New(command string) *Provider {
+	return &Provider{
+		kind:    credentialProcess,
+		command: command,
+		run:     RunCommand,
+		parse:   parseCredentials,
+		now:     time.Now,
+	}
+}
+
+//NewToken returns new Provider instance for token command, which prints bearer token
+func NewToken(command string) *Provider {
+	p := New(command)
+	p.kind = tokenCommand
+	p.parse = parseToken
+	return p
+}
+
+//Get returns Provider shared by every gateway in current process for credential process,
+//so that credential process runs only once until credentials expire
+func Get(command string) *Provider {
+	return get(providerKey{kind: credentialProcess, command: command}, New)
+}
+
+//GetToken returns Provider shared by every gateway in current process for token command,
+//so that token command runs only once
+func GetToken(command string) *Provider {
+	return get(providerKey{kind: tokenCommand, command: command}, NewToken)
+}
+
+func get(key providerKey, create func(command string) *Provider) *Provider {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p, ok := providers[key]; ok {
+		return p
+	}
+	p := create(key.command)
+	providers[key] = p
+	return p
+}
+
+//Retrieve returns cached credentials if they are not expired, else, runs credential process
+func (p *Provider) Retrieve() (entity.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached != nil && !p.isExpired(*p.cached) {
+		return *p.cached, nil
+	}
+	output, err := p.run(p.command)
+	if err != nil {
+		return entity.Credentials{}, fmt.Errorf("failed to run %s '%s' due to %v", p.kind, p.command, err)
+	}
+	result, err := p.parse(output)
+	if err != nil {
+		return entity.Credentials{}, fmt.Errorf("%s '%s' %v", p.kind, p.command, err)
+	}
+	p.cached = &result
+	return result, nil
+}
+
+//parseCredentials parses credentials printed as json by credential process
+func parseCredentials(output []byte) (entity.Credentials, error) {
+	var result entity.Credentials
+	if err := json.Unmarshal(output, &result); err != nil {
+		return entity.Credentials{}, fmt.Errorf("printed invalid json due to %v", err)
+	}
+	return result, validate(result)
+}
+
+//parseToken returns token printed by token command, surrounding spaces and new lines are removed
+func parseToken(output []byte) (entity.Credentials, error) {
+	token := strings.TrimSpace(string(output))
+	if len(token) < 1 {
+		return entity.Credentials{}, errors.New("didn't print any token")
+	}
+	return entity.Credentials{Token: token}, nil
+}
+
+//isExpired checks whether credentials are expired or will expire within expiry window
+func (p *Provider) isExpired(c entity.Credentials) bool {
+	if c.Expiration == nil {
+		return false
+	}
+	return !p.now().Add(expiryWindow).Before(*c.Expiration)
+}
+
+func validate(c entity.Credentials) error {
+	if len(c.UserName) > 0 && len(c.Password) > 0 {
+		return nil
+	}
+	if len(c.Token) > 0 {
+		return nil
+	}
+	return errors.New("didn't print either username and password or token")
+}
+
+//RunCommand runs command using platform's shell, so that command can have arguments and pipes, and returns its stdout
+func RunCommand(command string) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package credentials
+
+import (
+	"errors"
+	"opensearch-cli/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeProvider(outputs ...string) (*Provider, *int) {
+	calls := 0
+	p := New("vault-credentials opensearch")
+	p.run = func(command string) ([]byte, error) {
+		output := outputs[calls]
+		calls++
+		return []byte(output), nil
+	}
+	return p, &calls
+}
+
+func TestProviderRetrieve(t *testing.T) {
+	t.Run("credentials are cached without expiration", func(t *testing.T) {
+		p, calls := fakeProvider(`{"username":"admin","password":"secret"}`)
+		for i := 0; i < 3; i++ {
+			c, err := p.Retrieve()
+			assert.NoError(t, err)
+			assert.EqualValues(t, entity.Credentials{UserName: "admin", Password: "secret"}, c)
+		}
+		assert.EqualValues(t, 1, *calls)
+	})
+	t.Run("credentials are refreshed before expiration", func(t *testing.T) {
+		p, calls := fakeProvider(
+			`{"token":"first","expiration":"2021-06-01T10:05:00Z"}`,
+			`{"token":"second","expiration":"2021-06-01T11:05:00Z"}`)
+		now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+		p.now = func() time.Time { return now }
+		c, err := p.Retrieve()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "first", c.Token)
+		now = now.Add(3 * time.Minute)
+		c, err = p.Retrieve()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "first", c.Token)
+		now = now.Add(90 * time.Second)
+		c, err = p.Retrieve()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "second", c.Token)
+		assert.EqualValues(t, 2, *calls)
+	})
+	t.Run("invalid json", func(t *testing.T) {
+		p, _ := fakeProvider(`username=admin`)
+		_, err := p.Retrieve()
+		assert.EqualError(t, err, "credential process 'vault-credentials opensearch' printed invalid json due to invalid character 'u' looking for beginning of value")
+	})
+	t.Run("missing credentials", func(t *testing.T) {
+		p, _ := fakeProvider(`{"username":"admin"}`)
+		_, err := p.Retrieve()
+		assert.EqualError(t, err, "credential process 'vault-credentials opensearch' didn't print either username and password or token")
+	})
+	t.Run("command failed", func(t *testing.T) {
+		p := New("vault-credentials opensearch")
+		p.run = func(command string) ([]byte, error) {
+			return nil, errors.New("exit status 1: permission denied")
+		}
+		_, err := p.Retrieve()
+		assert.EqualError(t, err, "failed to run credential process 'vault-credentials opensearch' due to exit status 1: permission denied")
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("provider is shared for same command", func(t *testing.T) {
+		assert.Same(t, Get("echo one"), Get("echo one"))
+		assert.NotSame(t, Get("echo one"), Get("echo two"))
+		assert.Same(t, GetToken("echo one"), GetToken("echo one"))
+		assert.NotSame(t, Get("echo one"), GetToken("echo one"))
+	})
+}
+
+func TestTokenProvider(t *testing.T) {
+	t.Run("token is cached", func(t *testing.T) {
+		calls := 0
+		p := NewToken("vault-token opensearch")
+		p.run = func(command string) ([]byte, error) {
+			calls++
+			return []byte("  abc\n"), nil
+		}
+		for i := 0; i < 2; i++ {
+			c, err := p.Retrieve()
+			assert.NoError(t, err)
+			assert.EqualValues(t, entity.Credentials{Token: "abc"}, c)
+		}
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("no token", func(t *testing.T) {
+		p := NewToken("vault-token opensearch")
+		p.run = func(command string) ([]byte, error) {
+			return []byte("\n"), nil
+		}
+		_, err := p.Retrieve()
+		assert.EqualError(t, err, "token command 'vault-token opensearch' didn't print any token")
+	})
+}
+
+func TestRunCommand(t *testing.T) {
+	t.Run("stdout is returned", func(t *testing.T) {
+		output, err := RunCommand(`echo '{"token":"abc"}'`)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "{\"token\":\"abc\"}\n", string(output))
+	})
+}
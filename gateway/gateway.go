@@ -33,6 +33,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"opensearch-cli/client"
@@ -40,6 +41,7 @@ import (
 	"opensearch-cli/entity/platform"
 	"opensearch-cli/environment"
 	"opensearch-cli/gateway/aws/signer"
+	"opensearch-cli/gateway/credentials"
 	"os"
 	"strconv"
 	"time"
@@ -47,10 +49,16 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 )
 
+//defaultDialTimeout is used when dialer is replaced to apply keep-alive settings, same as http.DefaultTransport
+const defaultDialTimeout = 30 * time.Second
+
 //HTTPGateway type for gateway client
 type HTTPGateway struct {
-	Client  *client.Client
-	Profile *entity.Profile
+	Client      *client.Client
+	Profile     *entity.Profile
+	token       string
+	credentials *credentials.Provider
+	signer      *signer.Signer
 }
 
 //GetDefaultHeaders returns common headers
@@ -59,8 +67,37 @@ func GetDefaultHeaders() map[string]string {
 		"content-type": "application/json",
 	}
 }
+
+//tlsVersions maps supported values of min_tls_version to tls package constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+//GetTLSVersion returns tls package constant of min_tls_version value
+func GetTLSVersion(value string) (uint16, error) {
+	version, ok := tlsVersions[value]
+	if !ok {
+		return 0, fmt.Errorf("invalid min_tls_version %s. Options are 1.0, 1.1, 1.2, 1.3", value)
+	}
+	return version, nil
+}
+
+//GetTLSConfig builds tls config from trust settings, CA certificate is added to system's certificate pool
 func GetTLSConfig(trust *entity.Trust) (*tls.Config, error) {
-	config := &tls.Config{}
+	config := &tls.Config{
+		InsecureSkipVerify: trust.Insecure != nil && *trust.Insecure,
+		ServerName:         trust.ServerName,
+	}
+	if len(trust.MinTLSVersion) > 0 {
+		version, err := GetTLSVersion(trust.MinTLSVersion)
+		if err != nil {
+			return nil, err
+		}
+		config.MinVersion = version
+	}
 	if trust.ClientCertificateFilePath != nil && trust.ClientKeyFilePath != nil {
 		cert, err := tls.LoadX509KeyPair(*trust.ClientCertificateFilePath, *trust.ClientKeyFilePath)
 		if err != nil {
@@ -70,31 +107,63 @@ func GetTLSConfig(trust *entity.Trust) (*tls.Config, error) {
 		}
 		config.Certificates = []tls.Certificate{cert}
 	}
-	caCertPool := x509.NewCertPool()
 	if trust.CAFilePath != nil {
 		caCert, err := ioutil.ReadFile(*trust.CAFilePath)
 		if err != nil {
 			return nil, fmt.Errorf("error opening certificate file %s, error: %s", *trust.CAFilePath, err)
 		}
-		caCertPool.AppendCertsFromPEM(caCert)
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil || caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificate found in certificate file %s", *trust.CAFilePath)
+		}
 		config.RootCAs = caCertPool
 	}
 
 	return config, nil
 }
 
+//GetTransport returns underlying *http.Transport used by client, false if client uses custom http.RoundTripper.
+//Round trippers which wrap another round tripper, like client.Recorder, are unwrapped
+func GetTransport(c *client.Client) (*http.Transport, bool) {
+	tripper := c.HTTPClient.HTTPClient.Transport
+	for {
+		wrapper, ok := tripper.(interface{ Unwrap() http.RoundTripper })
+		if !ok {
+			break
+		}
+		tripper = wrapper.Unwrap()
+	}
+	transport, ok := tripper.(*http.Transport)
+	return transport, ok
+}
+
 //NewHTTPGateway creates new HTTPGateway instance
 func NewHTTPGateway(c *client.Client, p *entity.Profile) (*HTTPGateway, error) {
 
+	transport, ok := GetTransport(c)
 	if p.Certificate != nil {
 		tlsConfig, err := GetTLSConfig(p.Certificate)
 		if err != nil {
 			return nil, err
 		}
-		c.HTTPClient.HTTPClient.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
+		if ok {
+			transport.TLSClientConfig = tlsConfig
 		}
 	}
+	if ok {
+		if err := configureTransport(transport, p); err != nil {
+			return nil, err
+		}
+	} else if setting := connectionSetting(p); len(setting) > 0 && !isReplay(c) {
+		return nil, fmt.Errorf("%s settings of profile cannot be applied, since client uses custom http transport", setting)
+	}
+
+	if err := configureEndpoints(c, p); err != nil {
+		return nil, err
+	}
 
 	// set max retry if provided by command
 	if p.MaxRetry != nil {
@@ -105,6 +174,14 @@ func NewHTTPGateway(c *client.Client, p *entity.Profile) (*HTTPGateway, error) {
 		c.HTTPClient.RetryMax = *val
 	}
 
+	if p.Retry != nil {
+		policy, err := GetRetryPolicy(p.Retry)
+		if err != nil {
+			return nil, err
+		}
+		c.SetRetryPolicy(policy)
+	}
+
 	// set connection timeout if provided by command
 	if p.Timeout != nil {
 		c.HTTPClient.HTTPClient.Timeout = time.Duration(*p.Timeout) * time.Second
@@ -114,10 +191,126 @@ func NewHTTPGateway(c *client.Client, p *entity.Profile) (*HTTPGateway, error) {
 		c.HTTPClient.HTTPClient.Timeout = time.Duration(*duration) * time.Second
 	}
 
-	return &HTTPGateway{
+	token, err := GetBearerToken(p.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &HTTPGateway{
 		Client:  c,
 		Profile: p,
-	}, nil
+		token:   token,
+	}
+	if len(p.CredentialProcess) > 0 {
+		g.credentials = credentials.Get(p.CredentialProcess)
+	}
+	if p.AWS != nil {
+		//signer is shared by every gateway using same AWS IAM settings
+		if g.signer, err = signer.Get(*p.AWS); err != nil {
+			return nil, err
+		}
+	}
+	if err = g.configureSniff(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+//GetRetryPolicy builds retry policy from retry settings, settings which are not provided use default policy
+func GetRetryPolicy(settings *entity.Retry) (client.RetryPolicy, error) {
+	policy := client.DefaultRetryPolicy()
+	if settings.MinBackoff != nil {
+		policy.MinBackoff = time.Duration(*settings.MinBackoff) * time.Millisecond
+	}
+	if settings.MaxBackoff != nil {
+		policy.MaxBackoff = time.Duration(*settings.MaxBackoff) * time.Millisecond
+	}
+	if policy.MinBackoff < 0 || policy.MaxBackoff < policy.MinBackoff {
+		return policy, fmt.Errorf("invalid retry backoff, min_backoff %v should not be negative or greater than max_backoff %v",
+			policy.MinBackoff, policy.MaxBackoff)
+	}
+	if len(settings.StatusCodes) > 0 {
+		for _, code := range settings.StatusCodes {
+			if code < 100 || code > 599 {
+				return policy, fmt.Errorf("invalid retry status code %d", code)
+			}
+		}
+		policy.StatusCodes = settings.StatusCodes
+	}
+	if settings.HonorRetryAfter != nil {
+		policy.HonorRetryAfter = *settings.HonorRetryAfter
+	}
+	if settings.Jitter != nil {
+		policy.Jitter = *settings.Jitter
+	}
+	if settings.RetryNonIdempotent != nil {
+		policy.RetryNonIdempotent = *settings.RetryNonIdempotent
+	}
+	return policy, nil
+}
+
+//connectionSetting returns name of the first setting of profile which must be applied on *http.Transport, empty
+//if profile doesn't have any
+func connectionSetting(p *entity.Profile) string {
+	switch {
+	case p.Certificate != nil && *p.Certificate != (entity.Trust{}):
+		return "certificate"
+	case p.Proxy != nil && len(p.Proxy.URL) > 0:
+		return "proxy"
+	case p.Transport != nil && *p.Transport != (entity.Transport{}):
+		return "transport"
+	}
+	return ""
+}
+
+//isReplay checks whether client replays recorded responses, connection settings don't apply since nothing is sent
+func isReplay(c *client.Client) bool {
+	_, ok := c.HTTPClient.HTTPClient.Transport.(*client.Replayer)
+	return ok
+}
+
+//configureTransport applies proxy and connection settings from profile on transport. If profile doesn't
+//have proxy, proxy is selected from HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func configureTransport(transport *http.Transport, p *entity.Profile) error {
+	transport.Proxy = http.ProxyFromEnvironment
+	if p.Proxy != nil && len(p.Proxy.URL) > 0 {
+		proxyURL, err := url.Parse(p.Proxy.URL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %v due to %v", p.Proxy.URL, err)
+		}
+		if len(p.Proxy.UserName) > 0 {
+			proxyURL.User = url.UserPassword(p.Proxy.UserName, p.Proxy.Password)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	settings := p.Transport
+	if settings == nil {
+		return nil
+	}
+	if settings.MaxIdleConns != nil {
+		transport.MaxIdleConns = *settings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost != nil {
+		transport.MaxIdleConnsPerHost = *settings.MaxIdleConnsPerHost
+	}
+	if settings.MaxConnsPerHost != nil {
+		transport.MaxConnsPerHost = *settings.MaxConnsPerHost
+	}
+	if settings.IdleConnTimeout != nil {
+		transport.IdleConnTimeout = time.Duration(*settings.IdleConnTimeout) * time.Second
+	}
+	if settings.KeepAlive != nil {
+		// negative value disables keep-alive probes on connection
+		dialer := &net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: time.Duration(*settings.KeepAlive) * time.Second,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	if settings.DisableKeepAlives != nil {
+		transport.DisableKeepAlives = *settings.DisableKeepAlives
+	}
+	return nil
 }
 
 func overrideValue(p *entity.Profile, envVariable string) (*int, bool) {
@@ -140,6 +333,7 @@ func (g *HTTPGateway) isValidResponse(response *http.Response) error {
 
 		return platform.NewRequestError(
 			response.StatusCode,
+			response.Request.URL.String(),
 			response.Body,
 			fmt.Errorf("%d Client Error: %s for url: %s", response.StatusCode, response.Status, response.Request.URL))
 	}
@@ -148,6 +342,7 @@ func (g *HTTPGateway) isValidResponse(response *http.Response) error {
 
 		return platform.NewRequestError(
 			response.StatusCode,
+			response.Request.URL.String(),
 			response.Body,
 			fmt.Errorf("%d Server Error: %s for url: %s", response.StatusCode, response.Status, response.Request.URL))
 	}
@@ -156,12 +351,14 @@ func (g *HTTPGateway) isValidResponse(response *http.Response) error {
 
 //Execute calls request using http and check if status code is ok or not
 func (g *HTTPGateway) Execute(req *retryablehttp.Request) ([]byte, error) {
-	if g.Profile.AWS != nil {
+	if g.signer != nil {
 		//sign request
-		if err := signer.SignRequest(req, *g.Profile.AWS, signer.GetV4Signer); err != nil {
+		if err := g.signer.Sign(req); err != nil {
 			return nil, err
 		}
 	}
+	//retry policy decides whether request can be retried by its method, even if there isn't any response
+	req = req.WithContext(client.WithRequest(req.Context(), req.Method, req.URL.Path))
 	response, err := g.Client.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -189,7 +386,7 @@ func (g *HTTPGateway) Call(req *retryablehttp.Request, statusCode int) ([]byte,
 		return nil, err
 	}
 	if r.StatusCode() != statusCode {
-		return nil, fmt.Errorf(r.GetResponse())
+		return nil, platform.NewResponseError(r)
 	}
 	return nil, err
 
@@ -215,23 +412,103 @@ func (g *HTTPGateway) BuildCurlRequest(ctx context.Context, method string, paylo
 		return nil, err
 	}
 	req := r.WithContext(ctx)
-	if len(g.Profile.UserName) != 0 {
+	if g.credentials != nil {
+		//credentials from credential process are used instead of credentials from profile
+		c, err := g.credentials.Retrieve()
+		if err != nil {
+			return nil, err
+		}
+		setCredentials(req, c)
+		return setHeaders(req, headers), nil
+	}
+	switch {
+	case len(g.Profile.UserName) != 0:
 		req.SetBasicAuth(g.Profile.UserName, g.Profile.Password)
+	case len(g.token) != 0:
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	case len(g.Profile.APIKey) != 0:
+		req.Header.Set("Authorization", "ApiKey "+g.Profile.APIKey)
 	}
-	if len(headers) == 0 {
-		return req, nil
+	return setHeaders(req, headers), nil
+}
+
+//setCredentials sets authorization header from credentials printed by credential process
+func setCredentials(req *retryablehttp.Request, c entity.Credentials) {
+	if len(c.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		return
 	}
+	req.SetBasicAuth(c.UserName, c.Password)
+}
+
+//setHeaders sets headers on request, headers override existing values
+func setHeaders(req *retryablehttp.Request, headers map[string]string) *retryablehttp.Request {
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	return req, nil
+	return req
 }
 
 //GetValidEndpoint get url based on user config
 func GetValidEndpoint(profile *entity.Profile) (*url.URL, error) {
-	u, err := url.ParseRequestURI(profile.Endpoint)
+	endpoint := profile.Endpoint
+	if endpoints := GetEndpoints(profile); len(endpoints) > 0 {
+		endpoint = endpoints[0]
+	}
+	return parseEndpoint(endpoint)
+}
+
+//GetEndpoints returns endpoint of profile followed by endpoints of other nodes, without duplicates
+func GetEndpoints(profile *entity.Profile) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, endpoint := range append([]string{profile.Endpoint}, profile.Endpoints...) {
+		if len(endpoint) == 0 || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		result = append(result, endpoint)
+	}
+	return result
+}
+
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.ParseRequestURI(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("invalid endpoint: %v due to %v", profile.Endpoint, err)
+		return nil, fmt.Errorf("invalid endpoint: %v due to %v", endpoint, err)
 	}
 	return u, nil
 }
+
+func parseEndpoints(values []string) ([]*url.URL, error) {
+	var result []*url.URL
+	for _, value := range values {
+		u, err := parseEndpoint(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+	return result, nil
+}
+
+//configureEndpoints sends requests to every endpoint of profile, as selected by endpoint selection of profile
+func configureEndpoints(c *client.Client, p *entity.Profile) error {
+	nodes := GetEndpoints(p)
+	if len(nodes) < 2 {
+		return c.UseEndpoints(nil, client.Failover)
+	}
+	endpoints, err := parseEndpoints(nodes)
+	if err != nil {
+		return err
+	}
+	if p.AWS != nil {
+		//signature includes host, hence, request cannot be sent to another node once it is signed
+		return errors.New("multiple endpoints are not supported with aws-iam authentication")
+	}
+	selection := client.Failover
+	if len(p.EndpointSelection) > 0 {
+		selection = client.Selection(p.EndpointSelection)
+	}
+	return c.UseEndpoints(endpoints, selection)
+}
@@ -50,6 +50,51 @@ func (mr *MockGatewayMockRecorder) Curl(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Curl", reflect.TypeOf((*MockGateway)(nil).Curl), arg0, arg1)
 }
 
+// GetAuthInfo mocks base method
+func (m *MockGateway) GetAuthInfo(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthInfo", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthInfo indicates an expected call of GetAuthInfo
+func (mr *MockGatewayMockRecorder) GetAuthInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthInfo", reflect.TypeOf((*MockGateway)(nil).GetAuthInfo), arg0)
+}
+
+// GetClusterInfo mocks base method
+func (m *MockGateway) GetClusterInfo(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterInfo", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterInfo indicates an expected call of GetClusterInfo
+func (mr *MockGatewayMockRecorder) GetClusterInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterInfo", reflect.TypeOf((*MockGateway)(nil).GetClusterInfo), arg0)
+}
+
+// GetPlugins mocks base method
+func (m *MockGateway) GetPlugins(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlugins", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlugins indicates an expected call of GetPlugins
+func (mr *MockGatewayMockRecorder) GetPlugins(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlugins", reflect.TypeOf((*MockGateway)(nil).GetPlugins), arg0)
+}
+
 // SearchDistinctValues mocks base method
 func (m *MockGateway) SearchDistinctValues(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -34,9 +34,14 @@ import (
 	"opensearch-cli/entity"
 	"opensearch-cli/entity/platform"
 	gw "opensearch-cli/gateway"
+	"strings"
 )
 
-const search = "_search"
+const (
+	search   = "_search"
+	plugins  = "_cat/plugins"
+	authInfo = "_plugins/_security/authinfo"
+)
 
 //go:generate go run -mod=mod github.com/golang/mock/mockgen  -destination=mocks/mock_platform.go -package=mocks . Gateway
 
@@ -44,6 +49,9 @@ const search = "_search"
 type Gateway interface {
 	SearchDistinctValues(ctx context.Context, index string, field string) ([]byte, error)
 	Curl(ctx context.Context, request platform.CurlRequest) ([]byte, error)
+	GetClusterInfo(ctx context.Context) ([]byte, error)
+	GetPlugins(ctx context.Context) ([]byte, error)
+	GetAuthInfo(ctx context.Context) ([]byte, error)
 }
 
 type gateway struct {
@@ -129,3 +137,36 @@ func (g *gateway) buildURL(request platform.CurlRequest) (*url.URL, error) {
 	endpoint.RawQuery = request.QueryParams
 	return endpoint, nil
 }
+
+//GetClusterInfo gets cluster name and version from cluster root endpoint
+func (g *gateway) GetClusterInfo(ctx context.Context) ([]byte, error) {
+	return g.get(ctx, "", "")
+}
+
+//GetPlugins gets plugins installed on every node
+func (g *gateway) GetPlugins(ctx context.Context) ([]byte, error) {
+	return g.get(ctx, plugins, "format=json")
+}
+
+//GetAuthInfo gets authenticated user from security plugin
+func (g *gateway) GetAuthInfo(ctx context.Context) ([]byte, error) {
+	return g.get(ctx, authInfo, "")
+}
+
+//get calls GET request for given path and query on profile's endpoint
+func (g *gateway) get(ctx context.Context, path string, query string) ([]byte, error) {
+	endpoint, err := gw.GetValidEndpoint(g.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) > 0 {
+		//path is appended, since endpoint may be served under a path by reverse proxy
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + path
+	}
+	endpoint.RawQuery = query
+	request, err := g.BuildRequest(ctx, http.MethodGet, nil, endpoint.String(), gw.GetDefaultHeaders())
+	if err != nil {
+		return nil, err
+	}
+	return g.Call(request, http.StatusOK)
+}
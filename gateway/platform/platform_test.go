@@ -254,3 +254,43 @@ func TestGatewayCurl(t *testing.T) {
 		assert.EqualValues(t, 501, requestError.StatusCode())
 	})
 }
+
+func TestGatewayConnectionDetails(t *testing.T) {
+	ctx := context.Background()
+	p := &entity.Profile{
+		Endpoint: "http://localhost:9200",
+		UserName: "admin",
+		Password: "admin",
+	}
+	t.Run("get cluster info", func(t *testing.T) {
+		testClient := getCurlTestClient(t, "http://localhost:9200", []byte(``), map[string]string{}, `{"cluster_name":"opensearch"}`, 200)
+		testGateway, err := New(testClient, p)
+		assert.NoError(t, err)
+		actual, err := testGateway.GetClusterInfo(ctx)
+		assert.NoError(t, err)
+		assert.EqualValues(t, `{"cluster_name":"opensearch"}`, string(actual))
+	})
+	t.Run("get plugins", func(t *testing.T) {
+		testClient := getCurlTestClient(t, "http://localhost:9200/_cat/plugins?format=json", []byte(``), map[string]string{}, `[]`, 200)
+		testGateway, err := New(testClient, p)
+		assert.NoError(t, err)
+		actual, err := testGateway.GetPlugins(ctx)
+		assert.NoError(t, err)
+		assert.EqualValues(t, `[]`, string(actual))
+	})
+	t.Run("get auth info failed", func(t *testing.T) {
+		testClient := getCurlTestClient(t, "http://localhost:9200/_plugins/_security/authinfo", []byte(``), map[string]string{}, "Unauthorized", 401)
+		testGateway, err := New(testClient, p)
+		assert.NoError(t, err)
+		_, err = testGateway.GetAuthInfo(ctx)
+		assert.EqualError(t, err, "Unauthorized")
+	})
+	t.Run("endpoint with path", func(t *testing.T) {
+		testClient := getCurlTestClient(t, "https://localhost/opensearch/_cat/plugins?format=json", []byte(``), map[string]string{}, `[]`, 200)
+		testGateway, err := New(testClient, &entity.Profile{Endpoint: "https://localhost/opensearch/"})
+		assert.NoError(t, err)
+		actual, err := testGateway.GetPlugins(ctx)
+		assert.NoError(t, err)
+		assert.EqualValues(t, `[]`, string(actual))
+	})
+}
@@ -0,0 +1,222 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"opensearch-cli/client"
+	"opensearch-cli/entity"
+	"opensearch-cli/entity/platform"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//defaultSniffTTL is how long discovered nodes are used before cluster is asked for nodes again
+const defaultSniffTTL = 5 * time.Minute
+
+//sniffCacheDir returns folder where discovered nodes of every profile are cached
+var sniffCacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "opensearch-cli", "nodes"), nil
+}
+
+//sniffCache is content of cache file of profile
+type sniffCache struct {
+	Endpoints []string  `json:"endpoints"`
+	Expires   time.Time `json:"expires"`
+}
+
+//GetSniffTTL returns how long discovered nodes are cached
+func GetSniffTTL(settings *entity.Sniff) (time.Duration, error) {
+	if settings.TTL == nil {
+		return defaultSniffTTL, nil
+	}
+	if *settings.TTL < 0 {
+		return 0, fmt.Errorf("invalid sniff ttl %d, ttl should not be negative", *settings.TTL)
+	}
+	return time.Duration(*settings.TTL) * time.Second, nil
+}
+
+//configureSniff sends requests to data and coordinating nodes of cluster, discovered nodes are read from cache
+//if they are not expired. Endpoints of profile are used if none of discovered nodes can be connected to, or if
+//nodes cannot be discovered
+func (g *HTTPGateway) configureSniff() error {
+	p := g.Profile
+	if p.Sniff == nil || p.Sniff.Enabled == nil || !*p.Sniff.Enabled {
+		return nil
+	}
+	if p.AWS != nil {
+		//signature includes host, hence, request cannot be sent to another node once it is signed
+		return errors.New("sniffing is not supported with aws-iam authentication")
+	}
+	ttl, err := GetSniffTTL(p.Sniff)
+	if err != nil {
+		return err
+	}
+	endpoint, err := GetValidEndpoint(p)
+	if err != nil {
+		return err
+	}
+	cacheFile := sniffCacheFile(p)
+	nodes, ok := readSniffCache(cacheFile)
+	if !ok {
+		if nodes, err = g.discoverNodes(context.Background(), endpoint); err != nil {
+			//endpoints of profile are still used, request will fail there if cluster cannot be reached
+			g.traceWarning("failed to discover nodes, requests are sent to endpoints of profile", "error", err)
+			return nil
+		}
+		if len(nodes) == 0 {
+			g.traceWarning("no data or coordinating node is discovered, requests are sent to endpoints of profile")
+			return nil
+		}
+		writeSniffCache(cacheFile, sniffCache{Endpoints: nodes, Expires: time.Now().Add(ttl)})
+	}
+	endpoints, err := parseEndpoints(nodes)
+	if err != nil {
+		return err
+	}
+	//discovered nodes may not be reachable from client, like nodes which publish private addresses
+	fallback, err := parseEndpoints(GetEndpoints(p))
+	if err != nil {
+		return err
+	}
+	//nodes are discovered to distribute requests, unless profile asks for failover explicitly
+	selection := client.RoundRobin
+	if len(p.EndpointSelection) > 0 {
+		selection = client.Selection(p.EndpointSelection)
+	}
+	return g.Client.UseEndpointsWithFallback(endpoints, fallback, selection)
+}
+
+//traceWarning logs message through tracer of client, nothing is logged unless --debug or --trace is provided
+func (g *HTTPGateway) traceWarning(msg string, keysAndValues ...interface{}) {
+	if tracer, ok := g.Client.HTTPClient.Logger.(*client.Tracer); ok {
+		tracer.Warn(msg, keysAndValues...)
+	}
+}
+
+//discoverNodes calls nodes info API and returns endpoints of data and coordinating nodes, using scheme of endpoint
+func (g *HTTPGateway) discoverNodes(ctx context.Context, endpoint *url.URL) ([]string, error) {
+	nodesURL := *endpoint
+	nodesURL.Path = strings.TrimSuffix(nodesURL.Path, "/") + "/_nodes/http"
+	nodesURL.RawQuery = url.Values{"filter_path": []string{"nodes.*.roles,nodes.*.http.publish_address"}}.Encode()
+	request, err := g.BuildRequest(ctx, http.MethodGet, nil, nodesURL.String(), GetDefaultHeaders())
+	if err != nil {
+		return nil, err
+	}
+	response, err := g.Execute(request)
+	if err != nil {
+		return nil, err
+	}
+	var nodes platform.NodesHTTP
+	if err = json.Unmarshal(response, &nodes); err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, node := range nodes.Nodes {
+		address := publishAddress(node.HTTP.PublishAddress)
+		if len(address) == 0 || !servesRequests(node.Roles) {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s://%s", endpoint.Scheme, address))
+	}
+	//nodes are returned by id, sort them so that round-robin starts from same node
+	sort.Strings(result)
+	return result, nil
+}
+
+//servesRequests checks whether node holds data, or is coordinating only node, which doesn't have any role
+func servesRequests(roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if strings.HasPrefix(role, "data") {
+			return true
+		}
+	}
+	return false
+}
+
+//publishAddress returns host:port of publish address, hostname is preferred over ip so that certificate
+//of node can be verified
+func publishAddress(address string) string {
+	parts := strings.SplitN(address, "/", 2)
+	if len(parts) == 1 {
+		return address
+	}
+	hostname, ip := parts[0], parts[1]
+	if len(hostname) == 0 {
+		return ip
+	}
+	idx := strings.LastIndex(ip, ":")
+	if idx < 0 {
+		return hostname
+	}
+	return hostname + ip[idx:]
+}
+
+//sniffCacheFile returns cache file of profile, file name depends on endpoints of profile too, so that
+//nodes are discovered again if profile is changed to connect to another cluster
+func sniffCacheFile(p *entity.Profile) string {
+	dir, err := sniffCacheDir()
+	if err != nil {
+		return ""
+	}
+	key := sha256.Sum256([]byte(p.Name + "\n" + strings.Join(GetEndpoints(p), "\n")))
+	return filepath.Join(dir, hex.EncodeToString(key[:8])+".json")
+}
+
+//readSniffCache returns cached nodes if they are not expired
+func readSniffCache(path string) ([]string, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var cache sniffCache
+	if err = json.Unmarshal(contents, &cache); err != nil {
+		return nil, false
+	}
+	if len(cache.Endpoints) == 0 || time.Now().After(cache.Expires) {
+		return nil, false
+	}
+	return cache.Endpoints, true
+}
+
+//writeSniffCache saves discovered nodes, cache is best effort, nodes are discovered again if it cannot be saved
+func writeSniffCache(path string, cache sniffCache) {
+	if len(path) == 0 {
+		return
+	}
+	contents, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(path, contents, 0600)
+}
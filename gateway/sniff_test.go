@@ -0,0 +1,212 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"opensearch-cli/client"
+	"opensearch-cli/client/mocks"
+	"opensearch-cli/entity"
+	"opensearch-cli/mapper"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//newSniffServer returns cluster whose nodes info API returns data node at the server itself, master only node and
+//coordinating node at coordinating
+func newSniffServer(t *testing.T, coordinating string, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_nodes/http" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		user, password, _ := r.BasicAuth()
+		assert.EqualValues(t, "admin:admin", user+":"+password)
+		assert.EqualValues(t, "nodes.*.roles,nodes.*.http.publish_address", r.URL.Query().Get("filter_path"))
+		host := r.Host
+		fmt.Fprintf(w, `{"nodes":{
+			"a":{"roles":["data","ingest"],"http":{"publish_address":"localhost/%s"}},
+			"b":{"roles":["cluster_manager"],"http":{"publish_address":"10.0.0.2:9200"}},
+			"c":{"roles":[],"http":{"publish_address":"%s"}}}}`, host, coordinating)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func useSniffCacheDir(t *testing.T) string {
+	dir := t.TempDir()
+	original := sniffCacheDir
+	sniffCacheDir = func() (string, error) {
+		return dir, nil
+	}
+	t.Cleanup(func() {
+		sniffCacheDir = original
+	})
+	return dir
+}
+
+func sniffProfile(endpoint string, ttl int64) *entity.Profile {
+	return &entity.Profile{
+		Name:     "sniff",
+		Endpoint: endpoint,
+		UserName: "admin",
+		Password: "admin",
+		Sniff:    &entity.Sniff{Enabled: mapper.BoolToBoolPtr(true), TTL: &ttl},
+	}
+}
+
+func TestGatewaySniff(t *testing.T) {
+	t.Run("requests are distributed to discovered nodes", func(t *testing.T) {
+		useSniffCacheDir(t)
+		var coordinatingCalls, calls int32
+		coordinating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&coordinatingCalls, 1)
+		}))
+		defer coordinating.Close()
+		coordinatingURL, _ := url.Parse(coordinating.URL)
+		server := newSniffServer(t, coordinatingURL.Host, &calls)
+
+		testClient, err := client.New(nil)
+		assert.NoError(t, err)
+		g, err := NewHTTPGateway(testClient, sniffProfile(server.URL, 60))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, calls)
+		_, ok := testClient.HTTPClient.HTTPClient.Transport.(*client.EndpointPool)
+		assert.True(t, ok)
+
+		for i := 0; i < 4; i++ {
+			req, err := g.BuildRequest(context.Background(), http.MethodGet, nil, server.URL+"/_cat/indices", nil)
+			assert.NoError(t, err)
+			_, err = g.Execute(req)
+			assert.NoError(t, err)
+		}
+		assert.EqualValues(t, 2, coordinatingCalls, "requests should be sent to discovered nodes in turn")
+	})
+	t.Run("discovered nodes are cached", func(t *testing.T) {
+		dir := useSniffCacheDir(t)
+		var calls int32
+		server := newSniffServer(t, "127.0.0.1:1", &calls)
+		profile := sniffProfile(server.URL, 60)
+		for i := 0; i < 2; i++ {
+			testClient, err := client.New(nil)
+			assert.NoError(t, err)
+			_, err = NewHTTPGateway(testClient, profile)
+			assert.NoError(t, err)
+		}
+		assert.EqualValues(t, 1, calls)
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		assert.Len(t, files, 1)
+
+		endpoints, ok := readSniffCache(sniffCacheFile(profile))
+		assert.True(t, ok)
+		u, _ := url.Parse(server.URL)
+		assert.EqualValues(t, []string{"http://127.0.0.1:1", "http://localhost:" + u.Port()}, endpoints)
+	})
+	t.Run("nodes are discovered again after ttl", func(t *testing.T) {
+		useSniffCacheDir(t)
+		var calls int32
+		server := newSniffServer(t, "127.0.0.1:1", &calls)
+		profile := sniffProfile(server.URL, 60)
+		writeSniffCache(sniffCacheFile(profile), sniffCache{
+			Endpoints: []string{"http://node-1:9200"},
+			Expires:   time.Now().Add(-time.Second),
+		})
+		testClient, err := client.New(nil)
+		assert.NoError(t, err)
+		_, err = NewHTTPGateway(testClient, profile)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("endpoint of profile is used if nodes cannot be discovered", func(t *testing.T) {
+		useSniffCacheDir(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+		testClient, err := client.New(nil)
+		assert.NoError(t, err)
+		profile := sniffProfile(server.URL, 60)
+		_, err = NewHTTPGateway(testClient, profile)
+		assert.NoError(t, err)
+		_, ok := testClient.HTTPClient.HTTPClient.Transport.(*client.EndpointPool)
+		assert.False(t, ok)
+		_, ok = readSniffCache(sniffCacheFile(profile))
+		assert.False(t, ok, "failure should not be cached")
+	})
+	t.Run("endpoint of profile is used if discovered nodes cannot be connected to", func(t *testing.T) {
+		useSniffCacheDir(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_nodes/http" {
+				fmt.Fprint(w, `{"nodes":{"a":{"roles":["data"],"http":{"publish_address":"127.0.0.1:1"}}}}`)
+				return
+			}
+			fmt.Fprint(w, "profile endpoint")
+		}))
+		defer server.Close()
+		testClient, err := client.New(nil)
+		assert.NoError(t, err)
+		testClient.HTTPClient.RetryMax = 0
+		g, err := NewHTTPGateway(testClient, sniffProfile(server.URL, 60))
+		assert.NoError(t, err)
+		req, err := g.BuildRequest(context.Background(), http.MethodGet, nil, server.URL+"/_cat/indices", nil)
+		assert.NoError(t, err)
+		response, err := g.Execute(req)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "profile endpoint", string(response))
+	})
+	t.Run("discovery failure is traced", func(t *testing.T) {
+		useSniffCacheDir(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+		testClient, err := client.New(nil)
+		assert.NoError(t, err)
+		var trace bytes.Buffer
+		testClient.EnableTrace(&trace, client.TraceHeaders)
+		_, err = NewHTTPGateway(testClient, sniffProfile(server.URL, 60))
+		assert.NoError(t, err)
+		assert.Contains(t, trace.String(), "* failed to discover nodes, requests are sent to endpoints of profile, error: ")
+	})
+	t.Run("invalid ttl", func(t *testing.T) {
+		_, err := NewHTTPGateway(mocks.NewTestClient(nil), sniffProfile("https://node-1:9200", -1))
+		assert.EqualError(t, err, "invalid sniff ttl -1, ttl should not be negative")
+	})
+	t.Run("aws-iam with sniffing", func(t *testing.T) {
+		profile := sniffProfile("https://node-1:9200", 60)
+		profile.AWS = &entity.AWSIAM{ServiceName: "es", Region: "us-west-2"}
+		_, err := NewHTTPGateway(mocks.NewTestClient(nil), profile)
+		assert.EqualError(t, err, "sniffing is not supported with aws-iam authentication")
+	})
+}
+
+func TestPublishAddress(t *testing.T) {
+	assert.EqualValues(t, "10.0.0.1:9200", publishAddress("10.0.0.1:9200"))
+	assert.EqualValues(t, "node-1:9200", publishAddress("node-1/10.0.0.1:9200"))
+	assert.EqualValues(t, "[::1]:9200", publishAddress("/[::1]:9200"))
+	assert.EqualValues(t, "node-1:9200", publishAddress("node-1/[::1]:9200"))
+	assert.EqualValues(t, "", publishAddress(""))
+}
+
+func TestServesRequests(t *testing.T) {
+	assert.True(t, servesRequests(nil))
+	assert.True(t, servesRequests([]string{"data_hot", "ingest"}))
+	assert.False(t, servesRequests([]string{"cluster_manager"}))
+	assert.False(t, servesRequests([]string{"master", "ml"}))
+}
@@ -0,0 +1,30 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package gateway
+
+import (
+	"opensearch-cli/entity"
+	"opensearch-cli/gateway/credentials"
+)
+
+//GetBearerToken returns static token from profile, or runs token command and returns its output. Output of
+//token command is shared by every gateway in current process, so that command runs only once
+func GetBearerToken(token *entity.Token) (string, error) {
+	if token == nil {
+		return "", nil
+	}
+	if len(token.Value) > 0 || len(token.Command) < 1 {
+		return token.Value, nil
+	}
+	c, err := credentials.GetToken(token.Command).Retrieve()
+	if err != nil {
+		return "", err
+	}
+	return c.Token, nil
+}
@@ -53,3 +53,14 @@ func (h *Handler) Curl(request entity.CurlCommandRequest) ([]byte, error) {
 	ctx := context.Background()
 	return h.Controller.Curl(ctx, request)
 }
+
+//CheckConnection checks whether cluster can be reached and user can be authenticated
+func CheckConnection(h *Handler, endpoint string) (entity.ConnectionReport, error) {
+	return h.CheckConnection(endpoint)
+}
+
+//CheckConnection checks whether cluster can be reached and user can be authenticated
+func (h *Handler) CheckConnection(endpoint string) (entity.ConnectionReport, error) {
+	ctx := context.Background()
+	return h.Controller.CheckConnection(ctx, endpoint)
+}
@@ -57,3 +57,28 @@ func TestHandlerCurl(t *testing.T) {
 		assert.EqualError(t, err, "failed to execute")
 	})
 }
+
+func TestHandlerCheckConnection(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	endpoint := "https://localhost:9200"
+	t.Run("success", func(t *testing.T) {
+		mockedController := mocks.NewMockController(mockCtrl)
+		expected := entity.ConnectionReport{Endpoint: endpoint, Reachable: true, TLS: "verified"}
+		mockedController.EXPECT().CheckConnection(ctx, endpoint).Return(expected, nil)
+		instance := New(mockedController)
+		report, err := CheckConnection(instance, endpoint)
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, report)
+	})
+	t.Run("failed to connect", func(t *testing.T) {
+		mockedController := mocks.NewMockController(mockCtrl)
+		expected := entity.ConnectionReport{Endpoint: endpoint, TLS: "unknown", Error: "connection refused"}
+		mockedController.EXPECT().CheckConnection(ctx, endpoint).Return(expected, errors.New("connection refused"))
+		instance := New(mockedController)
+		report, err := instance.CheckConnection(endpoint)
+		assert.EqualError(t, err, "connection refused")
+		assert.EqualValues(t, expected, report)
+	})
+}
@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 /*
@@ -36,9 +37,7 @@ import (
 	"opensearch-cli/client"
 	adctrl "opensearch-cli/controller/ad"
 	"opensearch-cli/controller/platform"
-	"opensearch-cli/entity"
 	adentity "opensearch-cli/entity/ad"
-	"opensearch-cli/environment"
 	adgateway "opensearch-cli/gateway/ad"
 	esg "opensearch-cli/gateway/platform"
 	"os"
@@ -82,13 +81,7 @@ func (a *ADTestSuite) SetupSuite() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	a.Profile = &entity.Profile{
-		Name:     "test",
-		Endpoint: os.Getenv(environment.OPENSEARCH_ENDPOINT),
-		UserName: os.Getenv(environment.OPENSEARCH_USER),
-		Password: os.Getenv(environment.OPENSEARCH_PASSWORD),
-	}
-	if err = a.ValidateProfile(); err != nil {
+	if err = a.SetupProfile(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -126,6 +119,7 @@ func (a *ADTestSuite) SetupSuite() {
 
 func (a *ADTestSuite) TearDownSuite() {
 	a.DeleteIndex(EcommerceIndexName)
+	a.StopServer()
 }
 
 // This will run right before the test starts
@@ -0,0 +1,222 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	detectorsIndex        = ".opendistro-anomaly-detectors"
+	detectorSchemaVersion = 0
+)
+
+type detector struct {
+	id         string
+	source     map[string]interface{}
+	version    int
+	seqNo      int
+	running    bool
+	lastUpdate int64
+}
+
+//anomalyDetection serves _plugins/_anomaly_detection/detectors APIs
+func (s *Server) anomalyDetection(r *request) response {
+	segments := r.segments[2:]
+	if len(segments) == 0 || segments[0] != "detectors" {
+		return unsupported(r)
+	}
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		return s.createDetector(r)
+	case len(segments) == 2 && segments[1] == "_search":
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			return unsupported(r)
+		}
+		return s.searchDetectors(r)
+	case len(segments) == 2:
+		switch r.Method {
+		case http.MethodGet:
+			return s.getDetector(segments[1])
+		case http.MethodPut:
+			return s.updateDetector(r, segments[1])
+		case http.MethodDelete:
+			return s.deleteDetector(segments[1])
+		}
+	case len(segments) == 3 && r.Method == http.MethodPost:
+		switch segments[2] {
+		case "_start":
+			return s.setDetectorState(segments[1], true)
+		case "_stop":
+			return s.setDetectorState(segments[1], false)
+		}
+	}
+	return unsupported(r)
+}
+
+func detectorNotFound(id string) response {
+	return errorResponse(http.StatusNotFound, "status_exception", fmt.Sprintf("Can't find detector with id: %s", id))
+}
+
+func detectorRunning(id string) response {
+	return errorResponse(http.StatusBadRequest, "status_exception", fmt.Sprintf("Detector job is running: %s", id))
+}
+
+//validateDetector checks name is unique and indices exist, id is excluded while checking name
+func (s *Server) validateDetector(source map[string]interface{}, id string) *response {
+	name, _ := source["name"].(string)
+	if len(name) == 0 {
+		result := errorResponse(http.StatusBadRequest, "parsing_exception", "name is missing")
+		return &result
+	}
+	for _, existing := range s.detectors {
+		if existing.id != id && existing.source["name"] == name {
+			result := errorResponse(http.StatusBadRequest, "illegal_argument_exception", fmt.Sprintf(
+				"Cannot create anomaly detector with name [%s] as it's already used by detector [%s]", name, existing.id))
+			return &result
+		}
+	}
+	indices, _ := source["indices"].([]interface{})
+	var expressions []string
+	for _, value := range indices {
+		expressions = append(expressions, fmt.Sprintf("%v", value))
+	}
+	if len(expressions) == 0 {
+		result := errorResponse(http.StatusBadRequest, "parsing_exception", "indices are missing")
+		return &result
+	}
+	names, err := s.resolveIndices(strings.Join(expressions, ","))
+	if err == nil && len(names) == 0 {
+		result := indexNotFound(strings.Join(expressions, ","))
+		err = &result
+	}
+	return err
+}
+
+func (s *Server) createDetector(r *request) response {
+	var source map[string]interface{}
+	if err := r.decode(&source); err != nil || source == nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse detector")
+	}
+	if err := s.validateDetector(source, ""); err != nil {
+		return *err
+	}
+	d := &detector{
+		id:      fmt.Sprintf("detector-%012d", s.nextSequence()),
+		source:  source,
+		version: 1,
+	}
+	d.update(s.nextSequence())
+	s.detectors[d.id] = d
+	result := d.result()
+	result["anomaly_detector"] = d.source
+	return response{status: http.StatusCreated, body: result}
+}
+
+//update sets fields which are generated by AD plugin
+func (d *detector) update(seqNo int) {
+	d.seqNo = seqNo
+	d.lastUpdate = time.Now().UnixNano() / int64(time.Millisecond)
+	d.source["schema_version"] = detectorSchemaVersion
+	d.source["last_update_time"] = d.lastUpdate
+	if _, ok := d.source["filter_query"]; !ok {
+		d.source["filter_query"] = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+}
+
+func (d *detector) result() map[string]interface{} {
+	return map[string]interface{}{
+		"_id":           d.id,
+		"_version":      d.version,
+		"_seq_no":       d.seqNo,
+		"_primary_term": 1,
+	}
+}
+
+func (s *Server) getDetector(id string) response {
+	d, ok := s.detectors[id]
+	if !ok {
+		return detectorNotFound(id)
+	}
+	result := d.result()
+	result["anomaly_detector"] = d.source
+	return success(result)
+}
+
+func (s *Server) updateDetector(r *request, id string) response {
+	d, ok := s.detectors[id]
+	if !ok {
+		return detectorNotFound(id)
+	}
+	if d.running {
+		return detectorRunning(id)
+	}
+	var source map[string]interface{}
+	if err := r.decode(&source); err != nil || source == nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse detector")
+	}
+	if err := s.validateDetector(source, id); err != nil {
+		return *err
+	}
+	d.source = source
+	d.version++
+	d.update(s.nextSequence())
+	result := d.result()
+	result["anomaly_detector"] = d.source
+	return success(result)
+}
+
+func (s *Server) deleteDetector(id string) response {
+	d, ok := s.detectors[id]
+	if !ok {
+		return detectorNotFound(id)
+	}
+	if d.running {
+		return detectorRunning(id)
+	}
+	delete(s.detectors, id)
+	return success(map[string]interface{}{
+		"_index":        detectorsIndex,
+		"_id":           id,
+		"_version":      d.version + 1,
+		"result":        "deleted",
+		"_shards":       map[string]interface{}{"total": 2, "successful": 1, "failed": 0},
+		"_seq_no":       s.nextSequence(),
+		"_primary_term": 1,
+	})
+}
+
+//setDetectorState starts or stops detector job, like AD plugin, starting running detector or stopping
+//stopped detector succeeds
+func (s *Server) setDetectorState(id string, running bool) response {
+	d, ok := s.detectors[id]
+	if !ok {
+		return detectorNotFound(id)
+	}
+	d.running = running
+	return success(d.result())
+}
+
+//searchDetectors runs search request on detectors, ordered by id, which is the order of creation
+func (s *Server) searchDetectors(r *request) response {
+	var ids []string
+	for id := range s.detectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var hits []hit
+	for _, id := range ids {
+		hits = append(hits, hit{index: detectorsIndex, id: id, source: s.detectors[id].source})
+	}
+	return searchHits(r, hits, 1)
+}
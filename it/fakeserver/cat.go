@@ -0,0 +1,152 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//table is response of cat APIs, rendered as text or json depending on 'format' query parameter
+type table struct {
+	columns []string
+	rows    [][]string
+}
+
+func (s *Server) cat(r *request) response {
+	if r.Method != http.MethodGet || len(r.segments) < 2 {
+		return unsupported(r)
+	}
+	expression := "_all"
+	if len(r.segments) > 2 {
+		expression = r.segments[2]
+	}
+	var result table
+	switch r.segments[1] {
+	case "count":
+		names, err := s.resolveIndices(expression)
+		if err != nil {
+			return *err
+		}
+		total := 0
+		for _, name := range names {
+			total += len(s.indices[name].docs)
+		}
+		now := time.Now().UTC()
+		result = table{
+			columns: []string{"epoch", "timestamp", "count"},
+			rows:    [][]string{{strconv.FormatInt(now.Unix(), 10), now.Format("15:04:05"), strconv.Itoa(total)}},
+		}
+	case "indices":
+		names, err := s.resolveIndices(expression)
+		if err != nil {
+			return *err
+		}
+		result.columns = []string{"health", "status", "index", "uuid", "pri", "rep", "docs.count"}
+		for _, name := range names {
+			idx := s.indices[name]
+			health := "green"
+			if idx.replicas > 0 {
+				health = "yellow"
+			}
+			result.rows = append(result.rows, []string{health, "open", name, name + "-uuid",
+				strconv.Itoa(idx.shards), strconv.Itoa(idx.replicas), strconv.Itoa(len(idx.docs))})
+		}
+	case "health":
+		result = table{
+			columns: []string{"cluster", "status", "node.total", "shards"},
+			rows:    [][]string{{ClusterName, s.health(), "1", strconv.Itoa(s.activeShards())}},
+		}
+	case "nodes":
+		result = table{
+			columns: []string{"ip", "node.role", "master", "name", "id"},
+			rows:    [][]string{{"127.0.0.1", "dimr", "*", NodeName, nodeID(r)}},
+		}
+	case "plugins":
+		result.columns = []string{"name", "component", "version"}
+		for _, component := range []string{"opensearch-anomaly-detection", "opensearch-knn", "opensearch-security"} {
+			result.rows = append(result.rows, []string{NodeName, component, Version + ".0"})
+		}
+	default:
+		return unsupported(r)
+	}
+	return result.render(r)
+}
+
+//nodeID returns full node id if 'full_id' is requested, else, short id like OpenSearch
+func nodeID(r *request) string {
+	if isSet(r.URL.Query().Get("full_id"), r.URL.Query()["full_id"]) {
+		return NodeID
+	}
+	return NodeID[:4]
+}
+
+//render selects columns by 'h' query parameter, adds header if 'v' is set, and renders json if 'format' is json
+func (t table) render(r *request) response {
+	query := r.URL.Query()
+	columns := t.columns
+	if h := query.Get("h"); len(h) > 0 {
+		columns = strings.Split(h, ",")
+	}
+	positions := make([]int, len(columns))
+	for i, column := range columns {
+		positions[i] = -1
+		for j, existing := range t.columns {
+			if existing == column {
+				positions[i] = j
+			}
+		}
+		if positions[i] < 0 {
+			return errorResponse(http.StatusBadRequest, "illegal_argument_exception", fmt.Sprintf("header [%s] is not valid", column))
+		}
+	}
+	var rows [][]string
+	for _, row := range t.rows {
+		selected := make([]string, len(columns))
+		for i, position := range positions {
+			selected[i] = row[position]
+		}
+		rows = append(rows, selected)
+	}
+	if query.Get("format") == "json" {
+		result := []interface{}{}
+		for _, row := range rows {
+			item := map[string]string{}
+			for i, column := range columns {
+				item[column] = row[i]
+			}
+			result = append(result, item)
+		}
+		return success(result)
+	}
+	if isSet(query.Get("v"), query["v"]) {
+		rows = append([][]string{columns}, rows...)
+	}
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, value := range row {
+			if len(value) > widths[i] {
+				widths[i] = len(value)
+			}
+		}
+	}
+	var builder strings.Builder
+	for _, row := range rows {
+		var cells []string
+		for i, value := range row {
+			cells = append(cells, fmt.Sprintf("%-*s", widths[i], value))
+		}
+		builder.WriteString(strings.TrimRight(strings.Join(cells, " "), " "))
+		builder.WriteString("\n")
+	}
+	return text(builder.String())
+}
@@ -0,0 +1,221 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package fakeserver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"opensearch-cli/client"
+	adctrl "opensearch-cli/controller/ad"
+	"opensearch-cli/controller/knn"
+	"opensearch-cli/controller/platform"
+	"opensearch-cli/entity"
+	adentity "opensearch-cli/entity/ad"
+	adgateway "opensearch-cli/gateway/ad"
+	knngateway "opensearch-cli/gateway/knn"
+	platformgateway "opensearch-cli/gateway/platform"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func helperSetup(t *testing.T) (*Server, *client.Client, *entity.Profile) {
+	server := NewWithCredentials("admin", "admin")
+	t.Cleanup(server.Close)
+	c, err := client.New(nil)
+	assert.NoError(t, err)
+	c.HTTPClient.RetryMax = 0
+	return server, c, &entity.Profile{
+		Name:     "test",
+		Endpoint: server.URL,
+		UserName: "admin",
+		Password: "admin",
+	}
+}
+
+func helperCall(t *testing.T, server *Server, method string, path string, body string) (int, string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	req.SetBasicAuth("admin", "admin")
+	res, err := server.Client().Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	return res.StatusCode, string(contents)
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _, _ := helperSetup(t)
+	res, err := server.Client().Get(server.URL)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.EqualValues(t, http.StatusUnauthorized, res.StatusCode)
+	status, body := helperCall(t, server, http.MethodGet, "/", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.Contains(t, body, ClusterName)
+}
+
+func TestDocuments(t *testing.T) {
+	server, _, _ := helperSetup(t)
+	bulk := `{"index":{"_index":"books","_id":1}}
+{"title":"The Definitive Guide","publisher":"oreilly"}
+{"index":{"_index":"books","_id":2}}
+{"title":"Taming Text","publisher":"manning"}
+{"delete":{"_index":"books","_id":1}}
+`
+	status, body := helperCall(t, server, http.MethodPost, "/_bulk?refresh", bulk)
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.Contains(t, body, `"errors":false`)
+
+	status, body = helperCall(t, server, http.MethodGet, "/books/_doc/2?filter_path=_source.title", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"_source":{"title":"Taming Text"}}`, body)
+
+	status, body = helperCall(t, server, http.MethodGet, "/books/_doc/1", "")
+	assert.EqualValues(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"_index":"books","_id":"1","found":false}`, body)
+
+	status, body = helperCall(t, server, http.MethodGet, "/_cat/count/books?v", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	assert.EqualValues(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "epoch"))
+	assert.True(t, strings.HasSuffix(lines[1], " 1"))
+
+	status, body = helperCall(t, server, http.MethodGet, "/_cat/nodes?full_id=true&h=id", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, NodeID+"\n", body)
+
+	status, _ = helperCall(t, server, http.MethodDelete, "/books", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	status, body = helperCall(t, server, http.MethodGet, "/books/_search", "")
+	assert.EqualValues(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "no such index [books]")
+}
+
+func TestPlatform(t *testing.T) {
+	server, c, profile := helperSetup(t)
+	helperCall(t, server, http.MethodPost, "/_bulk", `{"index":{"_index":"orders"}}
+{"currency":"EUR"}
+{"index":{"_index":"orders"}}
+{"currency":"USD"}
+{"index":{"_index":"orders"}}
+{"currency":"EUR"}
+`)
+	g, err := platformgateway.New(c, profile)
+	assert.NoError(t, err)
+	ctrl := platform.New(g)
+	t.Run("distinct values", func(t *testing.T) {
+		values, err := ctrl.GetDistinctValues(context.Background(), "orders", "currency")
+		assert.NoError(t, err)
+		assert.EqualValues(t, []interface{}{"EUR", "USD"}, values)
+	})
+	t.Run("check connection", func(t *testing.T) {
+		report, err := ctrl.CheckConnection(context.Background(), profile.Endpoint)
+		assert.NoError(t, err)
+		assert.True(t, report.Reachable)
+		assert.True(t, report.AnomalyDetection)
+		assert.True(t, report.KNN)
+		assert.EqualValues(t, ClusterName, report.ClusterName)
+		assert.EqualValues(t, "admin", report.User)
+	})
+}
+
+func TestNodes(t *testing.T) {
+	server, _, _ := helperSetup(t)
+	status, body := helperCall(t, server, http.MethodGet, "/_nodes/http?filter_path=nodes.*.http.publish_address", "")
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"nodes":{"`+NodeID+`":{"http":{"publish_address":"`+server.Listener.Addr().String()+`"}}}}`, body)
+	status, _ = helperCall(t, server, http.MethodGet, "/_nodes/stats", "")
+	assert.EqualValues(t, http.StatusBadRequest, status)
+}
+
+func TestAnomalyDetection(t *testing.T) {
+	server, c, profile := helperSetup(t)
+	helperCall(t, server, http.MethodPost, "/_bulk", `{"index":{"_index":"ecommerce"}}
+{"currency":"EUR","total_quantity":2}
+`)
+	g, err := adgateway.New(c, profile)
+	assert.NoError(t, err)
+	pg, err := platformgateway.New(c, profile)
+	assert.NoError(t, err)
+	ctrl := adctrl.New(os.Stdin, platform.New(pg), g)
+	ctx := context.Background()
+	request := adentity.CreateDetectorRequest{
+		Name:      "orders",
+		TimeField: "utc_time",
+		Index:     []string{"ecommerce"},
+		Features: []adentity.FeatureRequest{{
+			AggregationType: []string{"sum"},
+			Enabled:         true,
+			Field:           []string{"total_quantity"},
+		}},
+		Interval: "1m",
+		Delay:    "1m",
+	}
+
+	id, err := ctrl.CreateAnomalyDetector(ctx, request)
+	assert.NoError(t, err)
+	assert.NotNil(t, id)
+	_, err = ctrl.CreateAnomalyDetector(ctx, request)
+	assert.EqualError(t, err, "Cannot create anomaly detector with name [orders] as it's already used by detector ["+*id+"]")
+	request.Name, request.Index = "missing", []string{"missing"}
+	_, err = ctrl.CreateAnomalyDetector(ctx, request)
+	assert.Error(t, err)
+
+	detectors, err := ctrl.SearchDetectorByName(ctx, "orders")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []adentity.Detector{{Name: "orders", ID: *id}}, detectors)
+
+	output, err := ctrl.GetDetector(ctx, *id)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "orders", output.Name)
+	assert.EqualValues(t, "1m", output.Interval)
+
+	assert.NoError(t, ctrl.StartDetector(ctx, *id))
+	err = g.DeleteDetector(ctx, *id)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Detector job is running: "+*id)
+	assert.NoError(t, ctrl.DeleteDetector(ctx, *id, false, true))
+	_, err = ctrl.GetDetector(ctx, *id)
+	assert.Error(t, err)
+}
+
+func TestKNN(t *testing.T) {
+	server, c, profile := helperSetup(t)
+	helperCall(t, server, http.MethodPut, "/vectors", `{"settings":{"number_of_shards":2,"number_of_replicas":0}}`)
+	g, err := knngateway.New(c, profile)
+	assert.NoError(t, err)
+	ctrl := knn.New(g)
+	ctx := context.Background()
+	t.Run("stats", func(t *testing.T) {
+		response, err := ctrl.GetStatistics(ctx, "", "knn_query_requests")
+		assert.NoError(t, err)
+		var stats struct {
+			Nodes map[string]map[string]interface{} `json:"nodes"`
+		}
+		assert.NoError(t, json.Unmarshal(response, &stats))
+		assert.EqualValues(t, map[string]map[string]interface{}{NodeID: {"knn_query_requests": 0.0}}, stats.Nodes)
+		_, err = ctrl.GetStatistics(ctx, NodeID, "invalid")
+		assert.Error(t, err)
+	})
+	t.Run("warmup", func(t *testing.T) {
+		response, err := ctrl.WarmupIndices(ctx, []string{"vectors"})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, response.Total)
+		assert.EqualValues(t, 2, response.Successful)
+		_, err = ctrl.WarmupIndices(ctx, []string{"missing"})
+		assert.EqualError(t, err, "no such index [missing]")
+	})
+}
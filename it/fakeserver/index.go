@@ -0,0 +1,817 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package fakeserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultShards   = 1
+	defaultReplicas = 1
+)
+
+type index struct {
+	name     string
+	shards   int
+	replicas int
+	mappings map[string]interface{}
+	ids      []string
+	docs     map[string]*document
+}
+
+type document struct {
+	source  map[string]interface{}
+	version int
+	seqNo   int
+}
+
+func newIndex(name string) *index {
+	return &index{
+		name:     name,
+		shards:   defaultShards,
+		replicas: defaultReplicas,
+		mappings: map[string]interface{}{},
+		docs:     map[string]*document{},
+	}
+}
+
+//health is yellow if any index has replicas, since replicas cannot be assigned on single node
+func (s *Server) health() string {
+	for _, idx := range s.indices {
+		if idx.replicas > 0 {
+			return "yellow"
+		}
+	}
+	return "green"
+}
+
+func (s *Server) activeShards() int {
+	count := 0
+	for _, idx := range s.indices {
+		count += idx.shards
+	}
+	return count
+}
+
+func (s *Server) cluster(r *request) response {
+	if len(r.segments) == 2 && r.segments[1] == "health" && r.Method == http.MethodGet {
+		unassigned := 0
+		for _, idx := range s.indices {
+			unassigned += idx.shards * idx.replicas
+		}
+		return success(map[string]interface{}{
+			"cluster_name":                     ClusterName,
+			"status":                           s.health(),
+			"timed_out":                        false,
+			"number_of_nodes":                  1,
+			"number_of_data_nodes":             1,
+			"active_primary_shards":            s.activeShards(),
+			"active_shards":                    s.activeShards(),
+			"relocating_shards":                0,
+			"initializing_shards":              0,
+			"unassigned_shards":                unassigned,
+			"delayed_unassigned_shards":        0,
+			"number_of_pending_tasks":          0,
+			"number_of_in_flight_fetch":        0,
+			"task_max_waiting_in_queue_millis": 0,
+		})
+	}
+	return unsupported(r)
+}
+
+//resolveIndices returns sorted names of indices matched by comma separated names and wildcard patterns
+func (s *Server) resolveIndices(expression string) ([]string, *response) {
+	matched := map[string]bool{}
+	for _, name := range strings.Split(expression, ",") {
+		if name == "_all" || name == "*" || name == "" {
+			name = "*"
+		}
+		if !strings.Contains(name, "*") {
+			if _, ok := s.indices[name]; !ok {
+				result := indexNotFound(name)
+				return nil, &result
+			}
+			matched[name] = true
+			continue
+		}
+		for existing := range s.indices {
+			if ok, _ := path.Match(name, existing); ok {
+				matched[existing] = true
+			}
+		}
+	}
+	var names []string
+	for name := range matched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+//indexAPI serves APIs whose first segment is index name
+func (s *Server) indexAPI(r *request) response {
+	name := r.segments[0]
+	if len(r.segments) == 1 {
+		switch r.Method {
+		case http.MethodPut:
+			return s.createIndex(r, name)
+		case http.MethodDelete:
+			return s.deleteIndex(name)
+		case http.MethodGet, http.MethodHead:
+			return s.getIndex(name)
+		}
+		return unsupported(r)
+	}
+	switch r.segments[1] {
+	case "_doc":
+		return s.documentAPI(r, name)
+	case "_bulk":
+		return s.bulk(r, name)
+	case "_search":
+		return s.search(r, name)
+	case "_count":
+		return s.count(name)
+	case "_refresh":
+		names, err := s.resolveIndices(name)
+		if err != nil {
+			return *err
+		}
+		return success(map[string]interface{}{"_shards": shards(len(names))})
+	case "_mapping":
+		return s.mapping(r, name)
+	}
+	return unsupported(r)
+}
+
+func (s *Server) createIndex(r *request, name string) response {
+	if _, ok := s.indices[name]; ok {
+		return errorResponse(http.StatusBadRequest, "resource_already_exists_exception", fmt.Sprintf("index [%s] already exists", name))
+	}
+	var body struct {
+		Settings map[string]interface{} `json:"settings"`
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err := r.decode(&body); err != nil {
+		return errorResponse(http.StatusBadRequest, "parse_exception", err.Error())
+	}
+	idx := newIndex(name)
+	if shards, ok := intSetting(body.Settings, "number_of_shards"); ok {
+		idx.shards = shards
+	}
+	if replicas, ok := intSetting(body.Settings, "number_of_replicas"); ok {
+		idx.replicas = replicas
+	}
+	if body.Mappings != nil {
+		idx.mappings = body.Mappings
+	}
+	s.indices[name] = idx
+	return success(map[string]interface{}{
+		"acknowledged":        true,
+		"shards_acknowledged": true,
+		"index":               name,
+	})
+}
+
+//intSetting reads setting which is provided either as 'name' or 'index.name', or nested in 'index'
+func intSetting(settings map[string]interface{}, name string) (int, bool) {
+	value, ok := settings[name]
+	if !ok {
+		value, ok = settings["index."+name]
+	}
+	if !ok {
+		if nested, isMap := settings["index"].(map[string]interface{}); isMap {
+			value, ok = nested[name]
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	result, err := strconv.Atoi(fmt.Sprint(value))
+	return result, err == nil
+}
+
+func (s *Server) deleteIndex(expression string) response {
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	for _, name := range names {
+		delete(s.indices, name)
+	}
+	return success(map[string]interface{}{"acknowledged": true})
+}
+
+func (s *Server) getIndex(expression string) response {
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	result := map[string]interface{}{}
+	for _, name := range names {
+		idx := s.indices[name]
+		result[name] = map[string]interface{}{
+			"aliases":  map[string]interface{}{},
+			"mappings": idx.mappings,
+			"settings": map[string]interface{}{
+				"index": map[string]interface{}{
+					"number_of_shards":   strconv.Itoa(idx.shards),
+					"number_of_replicas": strconv.Itoa(idx.replicas),
+					"provided_name":      name,
+				},
+			},
+		}
+	}
+	return success(result)
+}
+
+func (s *Server) mapping(r *request, expression string) response {
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	if r.Method == http.MethodPut {
+		var mappings map[string]interface{}
+		if decodeErr := r.decode(&mappings); decodeErr != nil {
+			return errorResponse(http.StatusBadRequest, "parse_exception", decodeErr.Error())
+		}
+		for _, name := range names {
+			s.indices[name].mappings = mergeFiltered(s.indices[name].mappings, mappings).(map[string]interface{})
+		}
+		return success(map[string]interface{}{"acknowledged": true})
+	}
+	result := map[string]interface{}{}
+	for _, name := range names {
+		result[name] = map[string]interface{}{"mappings": s.indices[name].mappings}
+	}
+	return success(result)
+}
+
+func (s *Server) count(expression string) response {
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	total := 0
+	for _, name := range names {
+		total += len(s.indices[name].docs)
+	}
+	return success(map[string]interface{}{
+		"count":   total,
+		"_shards": shards(len(names)),
+	})
+}
+
+func shards(total int) map[string]interface{} {
+	return map[string]interface{}{
+		"total":      total,
+		"successful": total,
+		"skipped":    0,
+		"failed":     0,
+	}
+}
+
+func (s *Server) documentAPI(r *request, name string) response {
+	switch {
+	case len(r.segments) == 2 && r.Method == http.MethodPost:
+		return s.indexDocument(r, name, "")
+	case len(r.segments) != 3:
+		return unsupported(r)
+	}
+	id := r.segments[2]
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		return s.indexDocument(r, name, id)
+	case http.MethodGet, http.MethodHead:
+		return s.getDocument(name, id)
+	case http.MethodDelete:
+		return s.deleteDocument(name, id)
+	}
+	return unsupported(r)
+}
+
+func (s *Server) indexDocument(r *request, name string, id string) response {
+	var source map[string]interface{}
+	if err := r.decode(&source); err != nil {
+		return errorResponse(http.StatusBadRequest, "mapper_parsing_exception", err.Error())
+	}
+	result, status := s.put(name, id, source)
+	return response{status: status, body: result}
+}
+
+//put saves document in index, index is created if it doesn't exist, id is generated if it is empty
+func (s *Server) put(name string, id string, source map[string]interface{}) (map[string]interface{}, int) {
+	idx, ok := s.indices[name]
+	if !ok {
+		idx = newIndex(name)
+		s.indices[name] = idx
+	}
+	if len(id) == 0 {
+		id = fmt.Sprintf("doc-%d", s.nextSequence())
+	}
+	if source == nil {
+		source = map[string]interface{}{}
+	}
+	result, status := "created", http.StatusCreated
+	doc, exists := idx.docs[id]
+	if exists {
+		result, status = "updated", http.StatusOK
+		doc.version++
+	} else {
+		doc = &document{version: 1}
+		idx.docs[id] = doc
+		idx.ids = append(idx.ids, id)
+	}
+	doc.source = source
+	doc.seqNo = s.nextSequence()
+	return documentResult(name, id, doc, result), status
+}
+
+func documentResult(name string, id string, doc *document, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"_index":        name,
+		"_id":           id,
+		"_version":      doc.version,
+		"result":        result,
+		"_shards":       map[string]interface{}{"total": 2, "successful": 1, "failed": 0},
+		"_seq_no":       doc.seqNo,
+		"_primary_term": 1,
+	}
+}
+
+func (s *Server) getDocument(name string, id string) response {
+	idx, ok := s.indices[name]
+	if !ok {
+		return indexNotFound(name)
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return response{status: http.StatusNotFound, body: map[string]interface{}{"_index": name, "_id": id, "found": false}}
+	}
+	return success(map[string]interface{}{
+		"_index":        name,
+		"_id":           id,
+		"_version":      doc.version,
+		"_seq_no":       doc.seqNo,
+		"_primary_term": 1,
+		"found":         true,
+		"_source":       doc.source,
+	})
+}
+
+func (s *Server) deleteDocument(name string, id string) response {
+	result, status := s.remove(name, id)
+	return response{status: status, body: result}
+}
+
+//remove deletes document from index
+func (s *Server) remove(name string, id string) (map[string]interface{}, int) {
+	idx, ok := s.indices[name]
+	if !ok {
+		return indexNotFound(name).body.(map[string]interface{}), http.StatusNotFound
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return documentResult(name, id, &document{version: 1, seqNo: s.nextSequence()}, "not_found"), http.StatusNotFound
+	}
+	delete(idx.docs, id)
+	for i, existing := range idx.ids {
+		if existing == id {
+			idx.ids = append(idx.ids[:i], idx.ids[i+1:]...)
+			break
+		}
+	}
+	doc.version++
+	doc.seqNo = s.nextSequence()
+	return documentResult(name, id, doc, "deleted"), http.StatusOK
+}
+
+//bulk serves newline delimited index, create, update and delete actions
+func (s *Server) bulk(r *request, defaultIndex string) response {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return unsupported(r)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(r.body))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var items []interface{}
+	hasErrors := false
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		//_id can be a number
+		var action map[string]struct {
+			Index string      `json:"_index"`
+			ID    interface{} `json:"_id"`
+		}
+		actionDecoder := json.NewDecoder(bytes.NewReader(line))
+		actionDecoder.UseNumber()
+		if err := actionDecoder.Decode(&action); err != nil || len(action) != 1 {
+			return errorResponse(http.StatusBadRequest, "illegal_argument_exception", fmt.Sprintf("Malformed action/metadata line [%s]", line))
+		}
+		for name, metadata := range action {
+			id := ""
+			if metadata.ID != nil {
+				id = fmt.Sprintf("%v", metadata.ID)
+			}
+			indexName := metadata.Index
+			if len(indexName) == 0 {
+				indexName = defaultIndex
+			}
+			if len(indexName) == 0 {
+				return errorResponse(http.StatusBadRequest, "action_request_validation_exception", "Validation Failed: 1: index is missing;")
+			}
+			var result map[string]interface{}
+			var status int
+			switch name {
+			case "delete":
+				result, status = s.remove(indexName, id)
+			case "index", "create", "update":
+				if !scanner.Scan() {
+					return errorResponse(http.StatusBadRequest, "illegal_argument_exception", "The bulk request must be terminated by a newline [\\n]")
+				}
+				var source map[string]interface{}
+				decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+				decoder.UseNumber()
+				if err := decoder.Decode(&source); err != nil {
+					return errorResponse(http.StatusBadRequest, "parse_exception", err.Error())
+				}
+				result, status = s.bulkWrite(name, indexName, id, source)
+			default:
+				return errorResponse(http.StatusBadRequest, "illegal_argument_exception", fmt.Sprintf("Malformed action/metadata line [%s], found [%s]", line, name))
+			}
+			result["status"] = status
+			if status >= http.StatusBadRequest {
+				hasErrors = true
+			}
+			items = append(items, map[string]interface{}{name: result})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errorResponse(http.StatusBadRequest, "parse_exception", err.Error())
+	}
+	return success(map[string]interface{}{
+		"took":   1,
+		"errors": hasErrors,
+		"items":  items,
+	})
+}
+
+func (s *Server) bulkWrite(action string, name string, id string, source map[string]interface{}) (map[string]interface{}, int) {
+	var existing *document
+	if idx, ok := s.indices[name]; ok {
+		existing = idx.docs[id]
+	}
+	switch {
+	case action == "create" && existing != nil:
+		return errorResponse(http.StatusConflict, "version_conflict_engine_exception",
+			fmt.Sprintf("[%s]: version conflict, document already exists", id)).body.(map[string]interface{}), http.StatusConflict
+	case action == "update" && existing == nil:
+		return errorResponse(http.StatusNotFound, "document_missing_exception",
+			fmt.Sprintf("[%s]: document missing", id)).body.(map[string]interface{}), http.StatusNotFound
+	case action == "update":
+		doc, _ := source["doc"].(map[string]interface{})
+		merged := map[string]interface{}{}
+		for key, value := range existing.source {
+			merged[key] = value
+		}
+		for key, value := range doc {
+			merged[key] = value
+		}
+		source = merged
+	}
+	return s.put(name, id, source)
+}
+
+//hit is a document which can be matched by search query
+type hit struct {
+	index  string
+	id     string
+	source map[string]interface{}
+}
+
+func (s *Server) search(r *request, expression string) response {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		return unsupported(r)
+	}
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	var hits []hit
+	for _, name := range names {
+		idx := s.indices[name]
+		for _, id := range idx.ids {
+			hits = append(hits, hit{index: name, id: id, source: idx.docs[id].source})
+		}
+	}
+	return searchHits(r, hits, len(names))
+}
+
+//searchHits runs search request of r on hits, supports match_all, match, term, terms, exists and bool queries,
+//from, size and terms aggregations
+func searchHits(r *request, hits []hit, shardCount int) response {
+	var body struct {
+		From         int                               `json:"from"`
+		Size         *int                              `json:"size"`
+		Query        map[string]interface{}            `json:"query"`
+		Aggs         map[string]map[string]interface{} `json:"aggs"`
+		Aggregations map[string]map[string]interface{} `json:"aggregations"`
+	}
+	if err := r.decode(&body); err != nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", err.Error())
+	}
+	var matched []hit
+	for _, h := range hits {
+		isMatch, err := matchQuery(body.Query, h.source)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", err.Error())
+		}
+		if isMatch {
+			matched = append(matched, h)
+		}
+	}
+	size := 10
+	if body.Size != nil {
+		size = *body.Size
+	}
+	if value := r.URL.Query().Get("size"); len(value) > 0 {
+		size, _ = strconv.Atoi(value)
+	}
+	result := []interface{}{}
+	for i := body.From; i < len(matched) && i < body.From+size; i++ {
+		result = append(result, map[string]interface{}{
+			"_index":  matched[i].index,
+			"_id":     matched[i].id,
+			"_score":  1.0,
+			"_source": matched[i].source,
+		})
+	}
+	output := map[string]interface{}{
+		"took":      1,
+		"timed_out": false,
+		"_shards":   shards(shardCount),
+		"hits": map[string]interface{}{
+			"total":     map[string]interface{}{"value": len(matched), "relation": "eq"},
+			"max_score": 1.0,
+			"hits":      result,
+		},
+	}
+	aggs := body.Aggs
+	if aggs == nil {
+		aggs = body.Aggregations
+	}
+	if len(aggs) > 0 {
+		aggregations, err := aggregate(aggs, matched)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", err.Error())
+		}
+		output["aggregations"] = aggregations
+	}
+	return success(output)
+}
+
+//matchQuery checks whether source matches query, empty query matches every document
+func matchQuery(query map[string]interface{}, source map[string]interface{}) (bool, error) {
+	for queryType, value := range query {
+		var isMatch bool
+		var err error
+		switch queryType {
+		case "match_all":
+			isMatch = true
+		case "match", "match_phrase", "term":
+			isMatch, err = matchField(queryType, value, source)
+		case "terms":
+			isMatch, err = matchTerms(value, source)
+		case "exists":
+			params, _ := value.(map[string]interface{})
+			_, isMatch = lookup(source, fmt.Sprint(params["field"]))
+		case "bool":
+			isMatch, err = matchBool(value, source)
+		default:
+			return false, fmt.Errorf("unknown query [%s]", queryType)
+		}
+		if err != nil || !isMatch {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+//matchField matches term exactly, match queries match if value shares any word with field
+func matchField(queryType string, value interface{}, source map[string]interface{}) (bool, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok || len(params) != 1 {
+		return false, fmt.Errorf("[%s] query doesn't support multiple fields", queryType)
+	}
+	for field, expected := range params {
+		if options, isMap := expected.(map[string]interface{}); isMap {
+			expected = options["query"]
+			if queryType == "term" {
+				expected = options["value"]
+			}
+		}
+		actual, exists := lookup(source, field)
+		if !exists {
+			return false, nil
+		}
+		for _, item := range values(actual) {
+			if queryType == "term" && fmt.Sprint(item) == fmt.Sprint(expected) {
+				return true, nil
+			}
+			if queryType != "term" && sharesWord(fmt.Sprint(item), fmt.Sprint(expected)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func matchTerms(value interface{}, source map[string]interface{}) (bool, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("[terms] query malformed")
+	}
+	for field, expected := range params {
+		list, isList := expected.([]interface{})
+		if !isList {
+			return false, fmt.Errorf("[terms] query requires list of values for field [%s]", field)
+		}
+		actual, exists := lookup(source, field)
+		if !exists {
+			return false, nil
+		}
+		for _, item := range values(actual) {
+			for _, candidate := range list {
+				if fmt.Sprint(item) == fmt.Sprint(candidate) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func matchBool(value interface{}, source map[string]interface{}) (bool, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("[bool] query malformed")
+	}
+	for _, clause := range []string{"must", "filter"} {
+		for _, query := range clauses(params[clause]) {
+			isMatch, err := matchQuery(query, source)
+			if err != nil || !isMatch {
+				return false, err
+			}
+		}
+	}
+	for _, query := range clauses(params["must_not"]) {
+		isMatch, err := matchQuery(query, source)
+		if err != nil || isMatch {
+			return false, err
+		}
+	}
+	should := clauses(params["should"])
+	if len(should) == 0 {
+		return true, nil
+	}
+	for _, query := range should {
+		isMatch, err := matchQuery(query, source)
+		if err != nil || isMatch {
+			return isMatch, err
+		}
+	}
+	//should clauses are optional if bool query has must or filter clauses
+	return params["must"] != nil || params["filter"] != nil, nil
+}
+
+//clauses returns queries of bool clause, which is either a query or list of queries
+func clauses(value interface{}) []map[string]interface{} {
+	switch clause := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{clause}
+	case []interface{}:
+		var result []map[string]interface{}
+		for _, item := range clause {
+			if query, ok := item.(map[string]interface{}); ok {
+				result = append(result, query)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+//lookup returns value of dotted field from source, '.keyword' sub field refers to field itself
+func lookup(source map[string]interface{}, field string) (interface{}, bool) {
+	field = strings.TrimSuffix(field, ".keyword")
+	if value, ok := source[field]; ok {
+		return value, true
+	}
+	parts := strings.SplitN(field, ".", 2)
+	if len(parts) < 2 {
+		return nil, false
+	}
+	nested, ok := source[parts[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(nested, parts[1])
+}
+
+//values returns items of array field, or field itself
+func values(value interface{}) []interface{} {
+	if list, ok := value.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{value}
+}
+
+//sharesWord checks whether both texts have at least one word in common, like match query on text field
+func sharesWord(text string, query string) bool {
+	words := map[string]bool{}
+	for _, word := range tokenize(text) {
+		words[word] = true
+	}
+	for _, word := range tokenize(query) {
+		if words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	})
+}
+
+//aggregate supports terms aggregations
+func aggregate(aggs map[string]map[string]interface{}, hits []hit) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for name, agg := range aggs {
+		terms, ok := agg["terms"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("aggregation [%s] is not supported, only terms aggregation is supported", name)
+		}
+		field := fmt.Sprint(terms["field"])
+		size := 10
+		if value, exists := terms["size"]; exists {
+			size, _ = strconv.Atoi(fmt.Sprint(value))
+		}
+		counts := map[string]int{}
+		keys := map[string]interface{}{}
+		for _, h := range hits {
+			value, exists := lookup(h.source, field)
+			if !exists {
+				continue
+			}
+			for _, item := range values(value) {
+				key := fmt.Sprint(item)
+				counts[key]++
+				keys[key] = item
+			}
+		}
+		var sorted []string
+		for key := range counts {
+			sorted = append(sorted, key)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if counts[sorted[i]] != counts[sorted[j]] {
+				return counts[sorted[i]] > counts[sorted[j]]
+			}
+			return sorted[i] < sorted[j]
+		})
+		buckets := []interface{}{}
+		others := 0
+		for i, key := range sorted {
+			if i >= size {
+				others += counts[key]
+				continue
+			}
+			buckets = append(buckets, map[string]interface{}{"key": keys[key], "doc_count": counts[key]})
+		}
+		result[name] = map[string]interface{}{
+			"doc_count_error_upper_bound": 0,
+			"sum_other_doc_count":         others,
+			"buckets":                     buckets,
+		}
+	}
+	return result, nil
+}
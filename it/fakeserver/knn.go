@@ -0,0 +1,108 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//knnStats are node level stats of k-NN plugin, since fake server doesn't load graphs, every counter is zero
+var knnStats = []string{
+	"eviction_count", "miss_count", "graph_memory_usage", "graph_memory_usage_percentage",
+	"graph_index_requests", "graph_index_errors", "knn_query_requests", "graph_query_requests",
+	"graph_query_errors", "indices_in_cache", "cache_capacity_reached", "load_exception_count",
+	"hit_count", "load_success_count", "total_load_time", "script_compilations",
+	"script_compilation_errors", "script_query_requests", "script_query_errors",
+}
+
+//knn serves _plugins/_knn/stats, _plugins/_knn/{nodes}/stats/{names} and _plugins/_knn/warmup/{indices} APIs
+func (s *Server) knn(r *request) response {
+	segments := r.segments[2:]
+	if r.Method != http.MethodGet || len(segments) == 0 {
+		return unsupported(r)
+	}
+	switch {
+	case segments[0] == "stats" && len(segments) <= 2:
+		return s.knnStats(r, "", strings.Join(segments[1:], ""))
+	case segments[0] == "warmup" && len(segments) == 2:
+		return s.warmup(segments[1])
+	case len(segments) >= 2 && segments[1] == "stats" && len(segments) <= 3:
+		return s.knnStats(r, segments[0], strings.Join(segments[2:], ""))
+	}
+	return unsupported(r)
+}
+
+//knnStats returns stats of single node, if nodes don't include the node, nodes of response is empty
+func (s *Server) knnStats(r *request, nodes string, names string) response {
+	selected := knnStats
+	if len(names) > 0 {
+		selected = strings.Split(names, ",")
+		for _, name := range selected {
+			if !contains(knnStats, name) {
+				return errorResponse(http.StatusBadRequest, "illegal_argument_exception",
+					fmt.Sprintf("request [%s] contains unrecognized stat: [%s]", r.URL.Path, name))
+			}
+		}
+	}
+	result := map[string]interface{}{}
+	if len(nodes) == 0 || contains(strings.Split(nodes, ","), NodeID) || contains(strings.Split(nodes, ","), "_all") {
+		stats := map[string]interface{}{}
+		for _, name := range selected {
+			switch name {
+			case "indices_in_cache":
+				stats[name] = map[string]interface{}{}
+			case "cache_capacity_reached":
+				stats[name] = false
+			default:
+				stats[name] = 0
+			}
+		}
+		result[NodeID] = stats
+	}
+	return success(map[string]interface{}{
+		"_nodes": map[string]interface{}{
+			"total":      len(result),
+			"successful": len(result),
+			"failed":     0,
+		},
+		"cluster_name":              ClusterName,
+		"circuit_breaker_triggered": false,
+		"nodes":                     result,
+	})
+}
+
+//warmup succeeds on every primary shard of indices
+func (s *Server) warmup(expression string) response {
+	names, err := s.resolveIndices(expression)
+	if err != nil {
+		return *err
+	}
+	total := 0
+	for _, name := range names {
+		total += s.indices[name].shards
+	}
+	return success(map[string]interface{}{
+		"_shards": map[string]interface{}{
+			"total":      total,
+			"successful": total,
+			"failed":     0,
+		},
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
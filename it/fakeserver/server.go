@@ -0,0 +1,397 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+//Package fakeserver provides an in-memory OpenSearch cluster, which serves enough of OpenSearch REST API
+//used by opensearch-cli, so that integration tests can run without a cluster
+package fakeserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ClusterName = "test-cluster"
+	NodeName    = "fake-node"
+	NodeID      = "fake-node-id-0000000001"
+	Version     = "1.0.0"
+)
+
+//Server is an in-memory single node OpenSearch cluster served by httptest.Server. Server is safe for
+//concurrent use, every request is served while holding lock
+type Server struct {
+	*httptest.Server
+	user      string
+	password  string
+	lock      sync.Mutex
+	indices   map[string]*index
+	detectors map[string]*detector
+	sequence  int
+}
+
+//New starts server which doesn't require authentication, Close should be called to stop it
+func New() *Server {
+	return NewWithCredentials("", "")
+}
+
+//NewWithCredentials starts server which requires HTTP basic authentication using given user and password,
+//Close should be called to stop it
+func NewWithCredentials(user string, password string) *Server {
+	s := &Server{
+		user:      user,
+		password:  password,
+		indices:   map[string]*index{},
+		detectors: map[string]*detector{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+//Reset removes every index and detector
+func (s *Server) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.indices = map[string]*index{}
+	s.detectors = map[string]*detector{}
+}
+
+//request holds parsed request, path is split by '/' without leading and trailing '/'
+type request struct {
+	*http.Request
+	segments []string
+	body     []byte
+}
+
+//response is written as json, yaml or plain text
+type response struct {
+	status int
+	body   interface{}
+	text   string
+}
+
+func success(body interface{}) response {
+	return response{status: http.StatusOK, body: body}
+}
+
+func text(body string) response {
+	return response{status: http.StatusOK, text: body}
+}
+
+//errorResponse builds error response in the same format as OpenSearch
+func errorResponse(status int, errorType string, reason string) response {
+	cause := map[string]interface{}{
+		"type":   errorType,
+		"reason": reason,
+	}
+	return response{
+		status: status,
+		body: map[string]interface{}{
+			"error": map[string]interface{}{
+				"root_cause": []interface{}{cause},
+				"type":       errorType,
+				"reason":     reason,
+			},
+			"status": status,
+		},
+	}
+}
+
+func indexNotFound(name string) response {
+	return errorResponse(http.StatusNotFound, "index_not_found_exception", fmt.Sprintf("no such index [%s]", name))
+}
+
+func unsupported(r *request) response {
+	return errorResponse(http.StatusBadRequest, "illegal_argument_exception",
+		fmt.Sprintf("no handler found for uri [%s] and method [%s]", r.URL.Path, r.Method))
+}
+
+//serveHTTP is used as handler instead of http.ServeMux, since ServeMux redirects paths like
+//'_plugins/_knn//stats', which are valid in OpenSearch
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if !s.authenticate(req) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="OpenSearch Security"`)
+		s.write(w, req, errorResponse(http.StatusUnauthorized, "security_exception", "Unauthorized"))
+		return
+	}
+	body, err := readBody(req)
+	if err != nil {
+		s.write(w, req, errorResponse(http.StatusBadRequest, "parse_exception", err.Error()))
+		return
+	}
+	r := &request{
+		Request:  req,
+		segments: splitPath(req.URL.Path),
+		body:     body,
+	}
+	s.lock.Lock()
+	result := s.route(r)
+	s.lock.Unlock()
+	s.write(w, req, result)
+}
+
+func (s *Server) authenticate(req *http.Request) bool {
+	if len(s.user) == 0 {
+		return true
+	}
+	user, password, ok := req.BasicAuth()
+	return ok && user == s.user && password == s.password
+}
+
+//route finds handler by first segment of path, rest of the path is an index unless it is an API
+func (s *Server) route(r *request) response {
+	if len(r.segments) == 0 {
+		return s.clusterInfo(r)
+	}
+	switch r.segments[0] {
+	case "_cluster":
+		return s.cluster(r)
+	case "_cat":
+		return s.cat(r)
+	case "_bulk":
+		return s.bulk(r, "")
+	case "_search":
+		return s.search(r, "_all")
+	case "_nodes":
+		return s.nodes(r)
+	case "_plugins":
+		return s.plugins(r)
+	}
+	if strings.HasPrefix(r.segments[0], "_") {
+		return unsupported(r)
+	}
+	return s.indexAPI(r)
+}
+
+func (s *Server) clusterInfo(r *request) response {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return unsupported(r)
+	}
+	return success(map[string]interface{}{
+		"name":         NodeName,
+		"cluster_name": ClusterName,
+		"cluster_uuid": "fake-cluster-uuid",
+		"version": map[string]interface{}{
+			"distribution": "opensearch",
+			"number":       Version,
+		},
+		"tagline": "The OpenSearch Project: https://opensearch.org/",
+	})
+}
+
+//nodes returns info of the only node, which is both cluster manager and data node, only http info is supported
+func (s *Server) nodes(r *request) response {
+	if r.Method != http.MethodGet || len(r.segments) > 2 || (len(r.segments) == 2 && r.segments[1] != "http") {
+		return unsupported(r)
+	}
+	address := s.Listener.Addr().String()
+	return success(map[string]interface{}{
+		"_nodes":       map[string]interface{}{"total": 1, "successful": 1, "failed": 0},
+		"cluster_name": ClusterName,
+		"nodes": map[string]interface{}{
+			NodeID: map[string]interface{}{
+				"name":    NodeName,
+				"version": Version,
+				"roles":   []string{"cluster_manager", "data", "ingest"},
+				"http": map[string]interface{}{
+					"bound_address":   []string{address},
+					"publish_address": address,
+				},
+			},
+		},
+	})
+}
+
+func (s *Server) plugins(r *request) response {
+	if len(r.segments) < 2 {
+		return unsupported(r)
+	}
+	switch r.segments[1] {
+	case "_anomaly_detection":
+		return s.anomalyDetection(r)
+	case "_knn":
+		return s.knn(r)
+	case "_security":
+		if len(r.segments) == 3 && r.segments[2] == "authinfo" {
+			return success(map[string]interface{}{
+				"user_name":     s.user,
+				"backend_roles": []string{},
+				"roles":         []string{"all_access"},
+			})
+		}
+	}
+	return unsupported(r)
+}
+
+//write renders response honoring 'format', 'pretty' and 'filter_path' query parameters
+func (s *Server) write(w http.ResponseWriter, req *http.Request, result response) {
+	query := req.URL.Query()
+	if result.body == nil {
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		w.WriteHeader(result.status)
+		io.WriteString(w, result.text)
+		return
+	}
+	body := result.body
+	if filter := query.Get("filter_path"); len(filter) > 0 {
+		body = filterPath(toGeneric(body), strings.Split(filter, ","))
+	}
+	var contents []byte
+	var err error
+	switch {
+	case query.Get("format") == "yaml":
+		w.Header().Set("Content-Type", "application/yaml")
+		contents, err = yaml.Marshal(toGeneric(body))
+	case isSet(query.Get("pretty"), query["pretty"]):
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		contents, err = json.MarshalIndent(body, "", "  ")
+		contents = append(contents, '\n')
+	default:
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		contents, err = json.Marshal(body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, err.Error())
+		return
+	}
+	w.WriteHeader(result.status)
+	if req.Method != http.MethodHead {
+		w.Write(contents)
+	}
+}
+
+//isSet checks whether boolean query parameter is provided without value, or is true
+func isSet(value string, values []string) bool {
+	return len(values) > 0 && (value == "" || value == "true")
+}
+
+//toGeneric converts body to maps and slices, so that it can be filtered and rendered as yaml
+func toGeneric(body interface{}) interface{} {
+	contents, err := json.Marshal(body)
+	if err != nil {
+		return body
+	}
+	var result interface{}
+	if err = json.Unmarshal(contents, &result); err != nil {
+		return body
+	}
+	return result
+}
+
+//filterPath keeps only fields which match any of the dotted paths, '*' matches any field
+func filterPath(body interface{}, paths []string) interface{} {
+	var result interface{}
+	for _, path := range paths {
+		result = mergeFiltered(result, filterFields(body, strings.Split(strings.TrimSpace(path), ".")))
+	}
+	if result == nil {
+		return map[string]interface{}{}
+	}
+	return result
+}
+
+func filterFields(body interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return body
+	}
+	switch value := body.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, field := range value {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if filtered := filterFields(field, path[1:]); filtered != nil {
+				result[key] = filtered
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case []interface{}:
+		var result []interface{}
+		for _, item := range value {
+			if filtered := filterFields(item, path); filtered != nil {
+				result = append(result, filtered)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	}
+	return nil
+}
+
+func mergeFiltered(target interface{}, source interface{}) interface{} {
+	targetMap, ok := target.(map[string]interface{})
+	sourceMap, isMap := source.(map[string]interface{})
+	if !ok || !isMap {
+		if source == nil {
+			return target
+		}
+		return source
+	}
+	for key, value := range sourceMap {
+		targetMap[key] = mergeFiltered(targetMap[key], value)
+	}
+	return targetMap
+}
+
+//readBody reads request body, gzip compressed body is decompressed
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil || len(body) == 0 || !strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		return body, err
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress request body due to: %w", err)
+	}
+	defer gzipReader.Close()
+	return ioutil.ReadAll(gzipReader)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if len(path) == 0 {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+//decode parses json body of request, empty body is decoded as empty object
+func (r *request) decode(target interface{}) error {
+	if len(bytes.TrimSpace(r.body)) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(r.body))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+//nextSequence returns sequence number used for ids, versions and sequence numbers
+func (s *Server) nextSequence() int {
+	s.sequence++
+	return s.sequence
+}
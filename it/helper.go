@@ -34,6 +34,7 @@ import (
 	"opensearch-cli/client"
 	"opensearch-cli/entity"
 	"opensearch-cli/environment"
+	"opensearch-cli/it/fakeserver"
 	"os"
 	"path/filepath"
 
@@ -41,10 +42,41 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const (
+	fakeServerUser     = "admin"
+	fakeServerPassword = "admin"
+)
+
 type CLISuite struct {
 	suite.Suite
 	Client  *client.Client
 	Profile *entity.Profile
+	Server  *fakeserver.Server
+}
+
+//SetupProfile creates profile using environment variables, if endpoint is not provided, fake server is
+//started and used instead of cluster. StopServer should be called once suite is complete
+func (a *CLISuite) SetupProfile() error {
+	a.Profile = &entity.Profile{
+		Name:     "test",
+		Endpoint: os.Getenv(environment.OPENSEARCH_ENDPOINT),
+		UserName: os.Getenv(environment.OPENSEARCH_USER),
+		Password: os.Getenv(environment.OPENSEARCH_PASSWORD),
+	}
+	if a.Profile.Endpoint == "" {
+		a.Server = fakeserver.NewWithCredentials(fakeServerUser, fakeServerPassword)
+		a.Profile.Endpoint = a.Server.URL
+		a.Profile.UserName = fakeServerUser
+		a.Profile.Password = fakeServerPassword
+	}
+	return a.ValidateProfile()
+}
+
+//StopServer stops fake server if it was started by SetupProfile
+func (a *CLISuite) StopServer() {
+	if a.Server != nil {
+		a.Server.Close()
+	}
 }
 
 //HelperLoadBytes loads file from testdata and stream contents
@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 /*
@@ -34,8 +35,6 @@ import (
 	"net/http"
 	"opensearch-cli/client"
 	ctrl "opensearch-cli/controller/knn"
-	"opensearch-cli/entity"
-	"opensearch-cli/environment"
 	gateway "opensearch-cli/gateway/knn"
 	"os"
 	"strings"
@@ -65,13 +64,7 @@ func (a *KNNTestSuite) SetupSuite() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	a.Profile = &entity.Profile{
-		Name:     "test",
-		Endpoint: os.Getenv(environment.OPENSEARCH_ENDPOINT),
-		UserName: os.Getenv(environment.OPENSEARCH_USER),
-		Password: os.Getenv(environment.OPENSEARCH_PASSWORD),
-	}
-	if err = a.ValidateProfile(); err != nil {
+	if err = a.SetupProfile(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -81,6 +74,7 @@ func (a *KNNTestSuite) SetupSuite() {
 }
 func (a *KNNTestSuite) TearDownSuite() {
 	a.DeleteIndex(KNNSampleIndexFileName)
+	a.StopServer()
 }
 
 //GetNodesIDUsingRESTAPI helper to get node id using rest api
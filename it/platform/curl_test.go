@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 /*
@@ -33,9 +34,7 @@ import (
 	"fmt"
 	"opensearch-cli/client"
 	ctrl "opensearch-cli/controller/platform"
-	"opensearch-cli/entity"
 	"opensearch-cli/entity/platform"
-	"opensearch-cli/environment"
 	gateway "opensearch-cli/gateway/platform"
 	"opensearch-cli/it"
 	"os"
@@ -69,13 +68,7 @@ func (a *OpenSearchTestSuite) SetupSuite() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	a.Profile = &entity.Profile{
-		Name:     "test",
-		Endpoint: os.Getenv(environment.OPENSEARCH_ENDPOINT),
-		UserName: os.Getenv(environment.OPENSEARCH_USER),
-		Password: os.Getenv(environment.OPENSEARCH_PASSWORD),
-	}
-	if err = a.ValidateProfile(); err != nil {
+	if err = a.SetupProfile(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -85,6 +78,7 @@ func (a *OpenSearchTestSuite) SetupSuite() {
 }
 func (a *OpenSearchTestSuite) TearDownSuite() {
 	a.DeleteIndex(GetBulkIndexName)
+	a.StopServer()
 }
 
 func (a *OpenSearchTestSuite) TestCurlGet() {
@@ -34,6 +34,6 @@ import (
 func main() {
 	if err := commands.Execute(); err != nil {
 		// By default every command should handle their error message
-		os.Exit(1)
+		os.Exit(commands.ExitCode(err))
 	}
 }
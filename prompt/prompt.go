@@ -0,0 +1,153 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+//InputUnavailableError is returned when prompt requires user input, but input is either
+//disabled by user or cannot be read
+type InputUnavailableError struct {
+	Prompt string
+	Err    error
+}
+
+//Error inherits error interface to pass as error
+func (e *InputUnavailableError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to accept value from user for '%s' due to %v", e.Prompt, e.Err)
+	}
+	return fmt.Sprintf("user input is required for '%s', but prompts are disabled", e.Prompt)
+}
+
+//Unwrap returns underlying error
+func (e *InputUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+//Prompter asks user for input. If assumeYes is true, confirmations are accepted without prompting,
+//if noInput is true, any prompt that requires user input fails with InputUnavailableError
+type Prompter struct {
+	reader    *bufio.Reader
+	file      *os.File
+	writer    io.Writer
+	assumeYes bool
+	noInput   bool
+}
+
+//New returns new Prompter instance which reads user input from reader
+func New(reader io.Reader, assumeYes bool, noInput bool) *Prompter {
+	p := &Prompter{
+		reader:    bufio.NewReader(reader),
+		writer:    os.Stdout,
+		assumeYes: assumeYes,
+		noInput:   noInput,
+	}
+	if f, ok := reader.(*os.File); ok {
+		p.file = f
+	}
+	return p
+}
+
+//WithModes returns copy of prompter with given assumeYes and noInput values. Copy shares reader with p,
+//hence, input buffered by one of them is available to the other
+func (p *Prompter) WithModes(assumeYes bool, noInput bool) *Prompter {
+	c := *p
+	c.assumeYes = assumeYes
+	c.noInput = noInput
+	return &c
+}
+
+//Printf writes message for user, like the reason why a value is invalid, on the writer used by prompts
+func (p *Prompter) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(p.writer, format, args...)
+}
+
+//Confirm asks user to confirm the action described by message, returns true if user accepted it
+func (p *Prompter) Confirm(message string) (bool, error) {
+	if p.assumeYes {
+		return true, nil
+	}
+	if p.noInput {
+		return false, &InputUnavailableError{Prompt: strings.TrimSpace(message)}
+	}
+	if len(message) > 0 {
+		fmt.Fprint(p.writer, message)
+	}
+	for {
+		response, err := p.readLine()
+		if err != nil {
+			return false, &InputUnavailableError{Prompt: strings.TrimSpace(message), Err: err}
+		}
+		switch strings.ToLower(response) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		default:
+			fmt.Fprint(p.writer, "please type (y)es or (n)o and then press enter:")
+		}
+	}
+}
+
+//Text asks user for a value, isValid is used to validate the value and prompt again if it is invalid
+func (p *Prompter) Text(label string, isValid func(string) bool) (string, error) {
+	if p.noInput {
+		return "", &InputUnavailableError{Prompt: label}
+	}
+	fmt.Fprintf(p.writer, "%s: ", label)
+	for {
+		response, err := p.readLine()
+		if err != nil {
+			return "", &InputUnavailableError{Prompt: label, Err: err}
+		}
+		if isValid == nil || isValid(response) {
+			return response, nil
+		}
+	}
+}
+
+//MaskedText asks user for a value without displaying it on console, since credentials
+//like password should not be displayed on console for security reasons
+func (p *Prompter) MaskedText(label string, isValid func(string) bool) (string, error) {
+	if p.noInput {
+		return "", &InputUnavailableError{Prompt: label}
+	}
+	if p.file == nil || !term.IsTerminal(int(p.file.Fd())) {
+		return p.Text(label, isValid)
+	}
+	fmt.Fprintf(p.writer, "%s: ", label)
+	for {
+		maskedValue, err := term.ReadPassword(int(p.file.Fd()))
+		if err != nil {
+			return "", &InputUnavailableError{Prompt: label, Err: err}
+		}
+		value := string(maskedValue)
+		if isValid == nil || isValid(value) {
+			fmt.Fprintln(p.writer)
+			return value, nil
+		}
+	}
+}
+
+//readLine reads single line from reader without line separator and surrounding spaces
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil && !(err == io.EOF && len(line) > 0) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * The OpenSearch Contributors require contributions made to
+ * this file be licensed under the Apache-2.0 license or a
+ * compatible open source license.
+ */
+
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPrompter(input string, assumeYes bool, noInput bool) (*Prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	p := New(strings.NewReader(input), assumeYes, noInput)
+	p.writer = &out
+	return p, &out
+}
+
+func TestPrompterConfirm(t *testing.T) {
+	t.Run("accepted by user", func(t *testing.T) {
+		p, out := newTestPrompter("yes\n", false, false)
+		proceed, err := p.Confirm("Do you want to proceed? Y/N ")
+		assert.NoError(t, err)
+		assert.True(t, proceed)
+		assert.EqualValues(t, "Do you want to proceed? Y/N ", out.String())
+	})
+	t.Run("rejected by user after invalid response", func(t *testing.T) {
+		p, out := newTestPrompter("maybe\n\nN\n", false, false)
+		proceed, err := p.Confirm("Do you want to proceed? Y/N ")
+		assert.NoError(t, err)
+		assert.False(t, proceed)
+		assert.Contains(t, out.String(), "please type (y)es or (n)o and then press enter:")
+	})
+	t.Run("assume yes", func(t *testing.T) {
+		p, out := newTestPrompter("", true, true)
+		proceed, err := p.Confirm("Do you want to proceed? Y/N ")
+		assert.NoError(t, err)
+		assert.True(t, proceed)
+		assert.Empty(t, out.String())
+	})
+	t.Run("input disabled", func(t *testing.T) {
+		p, _ := newTestPrompter("yes\n", false, true)
+		proceed, err := p.Confirm("Do you want to proceed? Y/N ")
+		assert.False(t, proceed)
+		assert.EqualError(t, err, "user input is required for 'Do you want to proceed? Y/N', but prompts are disabled")
+		assert.IsType(t, &InputUnavailableError{}, err)
+	})
+	t.Run("input not available", func(t *testing.T) {
+		p, _ := newTestPrompter("", false, false)
+		proceed, err := p.Confirm("Do you want to proceed? Y/N ")
+		assert.False(t, proceed)
+		assert.EqualError(t, err, "failed to accept value from user for 'Do you want to proceed? Y/N' due to EOF")
+		assert.True(t, errors.Is(err, io.EOF))
+	})
+}
+
+func TestPrompterText(t *testing.T) {
+	notEmpty := func(s string) bool {
+		return len(s) > 0
+	}
+	t.Run("read value", func(t *testing.T) {
+		p, out := newTestPrompter("  admin \n", false, false)
+		value, err := p.Text("Username", notEmpty)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "admin", value)
+		assert.EqualValues(t, "Username: ", out.String())
+	})
+	t.Run("read value until valid", func(t *testing.T) {
+		p, _ := newTestPrompter("\nadmin", false, false)
+		value, err := p.Text("Username", notEmpty)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "admin", value)
+	})
+	t.Run("masked value without terminal", func(t *testing.T) {
+		p, _ := newTestPrompter("secret\n", false, false)
+		value, err := p.MaskedText("Password", notEmpty)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "secret", value)
+	})
+	t.Run("input disabled", func(t *testing.T) {
+		p, _ := newTestPrompter("admin\n", true, true)
+		_, err := p.Text("Username", notEmpty)
+		assert.EqualError(t, err, "user input is required for 'Username', but prompts are disabled")
+		_, err = p.MaskedText("Password", notEmpty)
+		assert.EqualError(t, err, "user input is required for 'Password', but prompts are disabled")
+	})
+}